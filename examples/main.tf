@@ -16,6 +16,9 @@ resource "keep_provider" "prometheus" {
     */
   }
   #install_webhook = true (optional)
+  #required_scopes = ["connectivity"] (optional)
+  #rollback_on_scope_failure = true (optional)
+  #pulling_enabled = false (optional)
 }
 
 resource "keep_workflow" "example_workflow" {
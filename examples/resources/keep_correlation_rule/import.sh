@@ -0,0 +1,2 @@
+# import by the id or the name of the correlation rule
+terraform import keep_correlation_rule.database_errors database-errors
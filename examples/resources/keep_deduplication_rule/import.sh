@@ -0,0 +1,2 @@
+# import by the id or the name of the deduplication rule
+terraform import keep_deduplication_rule.pagerduty pagerduty-dedup
@@ -0,0 +1,2 @@
+# import by the id of the extraction
+terraform import keep_extraction.example 12
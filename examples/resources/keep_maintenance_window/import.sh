@@ -0,0 +1,2 @@
+# import by the id of the maintenance window
+terraform import keep_maintenance_window.checkout_release 4
@@ -0,0 +1,5 @@
+# import by the id of the preset
+terraform import keep_preset.critical 6f2a9c1e-3b4d-4e8f-9a7b-2c5d8e1f0a3b
+
+# or by its name
+terraform import keep_preset.critical critical-alerts
@@ -0,0 +1,5 @@
+# import by the id of the installed provider
+terraform import keep_provider.prometheus 3f8ab0e1a7c94c6d9b2e5f3a1c0d7e42
+
+# or by its type and name
+terraform import keep_provider.prometheus prometheus/prometheus-dev
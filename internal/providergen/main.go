@@ -0,0 +1,203 @@
+// providergen generates the typed keep_provider_<type> resources from a snapshot of keep's /providers response.
+//
+// The snapshot is checked in as providers.json next to this file, refresh it with:
+//
+//	curl -H "X-API-KEY: $KEEP_API_KEY" $KEEP_BACKEND_URL/providers > internal/providergen/providers.json
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// catalog is the response of keep's /providers endpoint
+type catalog struct {
+	Providers []struct {
+		Type        string                 `json:"type"`
+		DisplayName string                 `json:"display_name"`
+		Config      map[string]configField `json:"config"`
+	} `json:"providers"`
+}
+
+// configField is the metadata keep exposes for every authentication config field of a provider
+type configField struct {
+	Required    bool        `json:"required"`
+	Description string      `json:"description"`
+	Sensitive   bool        `json:"sensitive"`
+	Type        string      `json:"type"`
+	Validation  string      `json:"validation"`
+	Default     interface{} `json:"default"`
+}
+
+type provider struct {
+	// Type is the provider type as keep knows it, it is sent to keep as is
+	Type string
+	// ResourceName is the type made a valid terraform name, the resource is named keep_provider_<ResourceName>
+	ResourceName string
+	DisplayName  string
+	Fields       []field
+}
+
+type field struct {
+	Name        string
+	Type        string
+	Required    bool
+	Optional    bool
+	Computed    bool
+	Sensitive   bool
+	Default     string
+	Description string
+}
+
+var invalidName = regexp.MustCompile(`[^a-z0-9_]+`)
+
+var source = template.Must(template.New("source").Parse(`// Code generated by providergen from {{ .Catalog }}; DO NOT EDIT.
+
+package keep
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+// typedProviders func returns the provider types of keep's provider catalog with their authentication config fields
+func typedProviders() map[string]typedProvider {
+	return map[string]typedProvider{
+		{{- range .Providers }}
+		{{ printf "%q" .Type }}: {
+			ResourceName: {{ printf "%q" .ResourceName }},
+			DisplayName: {{ printf "%q" .DisplayName }},
+			Fields: map[string]*schema.Schema{
+				{{- range .Fields }}
+				{{ printf "%q" .Name }}: {
+					Type: {{ .Type }},
+					{{- if .Required }}
+					Required: true,
+					{{- end }}
+					{{- if .Optional }}
+					Optional: true,
+					{{- end }}
+					{{- if .Computed }}
+					Computed: true,
+					{{- end }}
+					{{- if .Sensitive }}
+					Sensitive: true,
+					{{- end }}
+					{{- if .Default }}
+					Default: {{ .Default }},
+					{{- end }}
+					Description: {{ printf "%q" .Description }},
+				},
+				{{- end }}
+			},
+		},
+		{{- end }}
+	}
+}
+`))
+
+func main() {
+	catalogPath := flag.String("catalog", "providers.json", "path of the keep /providers response snapshot")
+	outputPath := flag.String("output", "resource_provider_types_gen.go", "path of the generated file")
+	flag.Parse()
+
+	content, err := os.ReadFile(*catalogPath)
+	if err != nil {
+		log.Fatalf("cannot read catalog: %s", err)
+	}
+
+	var c catalog
+	if err := json.Unmarshal(content, &c); err != nil {
+		log.Fatalf("cannot parse catalog: %s", err)
+	}
+
+	providers := make([]provider, 0, len(c.Providers))
+	resourceNames := make(map[string]string)
+	for _, p := range c.Providers {
+		if len(p.Config) == 0 {
+			// providers without config are installed without authentication, keep_provider covers them
+			continue
+		}
+
+		fields := make([]field, 0, len(p.Config))
+		for name, config := range p.Config {
+			fields = append(fields, newField(name, config))
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+
+		resourceName := invalidName.ReplaceAllString(strings.ToLower(p.Type), "_")
+		if other, ok := resourceNames[resourceName]; ok {
+			log.Fatalf("provider types %s and %s both map to keep_provider_%s", other, p.Type, resourceName)
+		}
+		resourceNames[resourceName] = p.Type
+
+		providers = append(providers, provider{
+			Type:         p.Type,
+			ResourceName: resourceName,
+			DisplayName:  p.DisplayName,
+			Fields:       fields,
+		})
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Type < providers[j].Type })
+
+	var buf bytes.Buffer
+	err = source.Execute(&buf, map[string]interface{}{
+		"Catalog":   strings.TrimPrefix(*catalogPath, "../"),
+		"Providers": providers,
+	})
+	if err != nil {
+		log.Fatalf("cannot render source: %s", err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("cannot format source: %s", err)
+	}
+
+	if err := os.WriteFile(*outputPath, formatted, 0644); err != nil {
+		log.Fatalf("cannot write output: %s", err)
+	}
+}
+
+// newField func maps the metadata of a config field to its terraform schema
+func newField(name string, config configField) field {
+	f := field{
+		Name:        name,
+		Type:        "schema.TypeString",
+		Required:    config.Required,
+		Optional:    !config.Required,
+		Sensitive:   config.Sensitive,
+		Description: config.Description,
+	}
+
+	switch {
+	case config.Type == "switch":
+		f.Type = "schema.TypeBool"
+	case config.Validation == "port" || config.Validation == "positive":
+		f.Type = "schema.TypeInt"
+	}
+
+	if config.Default != nil && !config.Required {
+		switch f.Type {
+		case "schema.TypeBool":
+			f.Default = fmt.Sprintf("%t", config.Default == true)
+		case "schema.TypeInt":
+			f.Default = fmt.Sprintf("%d", int(config.Default.(float64)))
+		default:
+			f.Default = strconv.Quote(fmt.Sprint(config.Default))
+		}
+		f.Description = fmt.Sprintf("%s (default: %v)", f.Description, config.Default)
+	}
+
+	// optional values keep fills in are read back, unless they are secrets or have a default
+	f.Computed = f.Optional && !f.Sensitive && f.Default == ""
+
+	return f
+}
@@ -0,0 +1,153 @@
+package keep
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// alertFields are the fields of keep alerts CEL conditions are evaluated against.
+// source is a list, keep's conditions use it both as a list and as a string so it isn't typed.
+var alertFields = map[string]*cel.Type{
+	"id":                 cel.StringType,
+	"name":               cel.StringType,
+	"status":             cel.StringType,
+	"severity":           cel.StringType,
+	"lastReceived":       cel.StringType,
+	"firingStartTime":    cel.StringType,
+	"environment":        cel.StringType,
+	"service":            cel.StringType,
+	"source":             cel.DynType,
+	"message":            cel.StringType,
+	"description":        cel.StringType,
+	"url":                cel.StringType,
+	"imageUrl":           cel.StringType,
+	"fingerprint":        cel.StringType,
+	"labels":             cel.MapType(cel.StringType, cel.DynType),
+	"pushed":             cel.BoolType,
+	"deleted":            cel.BoolType,
+	"dismissed":          cel.BoolType,
+	"dismissUntil":       cel.StringType,
+	"assignee":           cel.StringType,
+	"providerId":         cel.StringType,
+	"providerType":       cel.StringType,
+	"note":               cel.StringType,
+	"startedAt":          cel.StringType,
+	"isNoisy":            cel.BoolType,
+	"isDuplicate":        cel.BoolType,
+	"isFullDuplicate":    cel.BoolType,
+	"isPartialDuplicate": cel.BoolType,
+	"duplicateReason":    cel.StringType,
+	"enriched_fields":    cel.ListType(cel.StringType),
+	"group":              cel.BoolType,
+	"incident":           cel.StringType,
+	"event_id":           cel.StringType,
+	"trigger":            cel.StringType,
+	"tags":               cel.DynType,
+}
+
+// alertCELEnv returns the CEL environment with the fields of keep alerts declared
+var alertCELEnv = sync.OnceValues(func() (*cel.Env, error) {
+	options := make([]cel.EnvOption, 0, len(alertFields)+1)
+	for field, fieldType := range alertFields {
+		options = append(options, cel.Variable(field, fieldType))
+	}
+
+	// conditions like source.contains("prometheus") check whether a list has an element
+	options = append(options, cel.Function("contains",
+		cel.MemberOverload("list_contains", []*cel.Type{cel.ListType(cel.DynType), cel.DynType}, cel.BoolType,
+			cel.BinaryBinding(func(list ref.Val, element ref.Val) ref.Val {
+				return list.(traits.Container).Contains(element)
+			}),
+		),
+	))
+
+	return cel.NewEnv(options...)
+})
+
+// compileCELCondition func parses and type checks a CEL condition against the fields of keep alerts
+func compileCELCondition(condition string) (*cel.Ast, error) {
+	env, err := alertCELEnv()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create CEL environment: %s", err)
+	}
+
+	ast, issues := env.Compile(condition)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("condition must evaluate to a bool, got %s", ast.OutputType())
+	}
+
+	return ast, nil
+}
+
+// validateCELCondition func validates CEL conditions keep evaluates against alerts, empty conditions match every alert
+func validateCELCondition(v interface{}, path cty.Path) diag.Diagnostics {
+	condition := v.(string)
+	if condition == "" {
+		return nil
+	}
+
+	if _, err := compileCELCondition(condition); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid CEL condition",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
+
+// evalCELCondition func evaluates a compiled CEL condition against an alert, the fields missing from the alert
+// get the defaults keep gives them
+func evalCELCondition(ast *cel.Ast, alert map[string]interface{}) (bool, error) {
+	env, err := alertCELEnv()
+	if err != nil {
+		return false, fmt.Errorf("cannot create CEL environment: %s", err)
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return false, err
+	}
+
+	activation := make(map[string]interface{}, len(alertFields))
+	for field, fieldType := range alertFields {
+		if value, ok := alert[field]; ok && value != nil {
+			activation[field] = value
+			continue
+		}
+
+		switch {
+		case field == "source" || fieldType.Kind() == types.ListKind:
+			activation[field] = []interface{}{}
+		case fieldType.Kind() == types.MapKind:
+			activation[field] = map[string]interface{}{}
+		case fieldType.Kind() == types.BoolKind:
+			activation[field] = false
+		default:
+			activation[field] = ""
+		}
+	}
+
+	result, _, err := program.Eval(activation)
+	if err != nil {
+		return false, err
+	}
+
+	matched, ok := result.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("condition must evaluate to a bool, got %v", result.Value())
+	}
+	return matched, nil
+}
@@ -0,0 +1,256 @@
+package keep
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/overloads"
+	"github.com/google/cel-go/common/types"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// celSQLOperators maps the CEL comparison operators to the SQL ones
+var celSQLOperators = map[string]string{
+	operators.Equals:        "=",
+	operators.NotEquals:     "!=",
+	operators.Less:          "<",
+	operators.LessEquals:    "<=",
+	operators.Greater:       ">",
+	operators.GreaterEquals: ">=",
+}
+
+// celLikePatterns maps the CEL string functions to the LIKE patterns matching the same values
+var celLikePatterns = map[string]string{
+	overloads.Contains:   "%%%s%%",
+	overloads.StartsWith: "%s%%",
+	overloads.EndsWith:   "%%%s",
+}
+
+// likeEscaper escapes the characters LIKE patterns give a meaning to, the escape character is declared with ESCAPE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// sqlParamName matches the characters not allowed in the names of SQL parameters
+var sqlParamName = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// celToSQL func translates a CEL query to the parameterized SQL keep's rule editor builds its query from, with named
+// parameters like `(source = :source_1 and severity = :severity_1)`. Only the queries the editor can build are translated,
+// comparisons of alert fields with literals combined with &&, || and !.
+func celToSQL(query string) (string, map[string]interface{}, error) {
+	checked, err := compileCELCondition(query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	t := &celSQLTranslator{
+		params: make(map[string]interface{}),
+		counts: make(map[string]int),
+	}
+	sql, err := t.translate(checked.NativeRep().Expr())
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the editor wraps the query in parentheses
+	if !strings.HasPrefix(sql, "(") {
+		sql = "(" + sql + ")"
+	}
+	return sql, t.params, nil
+}
+
+// celSQLTranslator translates CEL expressions to SQL, collecting the literals as named parameters
+type celSQLTranslator struct {
+	params map[string]interface{}
+	// counts is the number of parameters of every field, parameters are named after their field
+	counts map[string]int
+}
+
+func (t *celSQLTranslator) translate(e ast.Expr) (string, error) {
+	if e.Kind() != ast.CallKind {
+		return "", fmt.Errorf("%s is not a condition, only comparisons of alert fields can be translated to SQL", describeCELExpr(e))
+	}
+
+	call := e.AsCall()
+	args := call.Args()
+
+	switch function := call.FunctionName(); function {
+	case operators.LogicalAnd, operators.LogicalOr:
+		left, err := t.translate(args[0])
+		if err != nil {
+			return "", err
+		}
+		right, err := t.translate(args[1])
+		if err != nil {
+			return "", err
+		}
+		joiner := " and "
+		if function == operators.LogicalOr {
+			joiner = " or "
+		}
+		return "(" + left + joiner + right + ")", nil
+
+	case operators.LogicalNot:
+		condition, err := t.translate(args[0])
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + condition + ")", nil
+
+	case operators.In:
+		field, err := celField(args[0])
+		if err != nil {
+			return "", err
+		}
+		if args[1].Kind() != ast.ListKind {
+			return "", fmt.Errorf("%s can only be compared with a list of values", field)
+		}
+		var params []string
+		for _, element := range args[1].AsList().Elements() {
+			value, err := celLiteral(element)
+			if err != nil {
+				return "", err
+			}
+			params = append(params, t.param(field, value))
+		}
+		return fmt.Sprintf("%s in (%s)", field, strings.Join(params, ", ")), nil
+
+	case overloads.Contains, overloads.StartsWith, overloads.EndsWith:
+		if !call.IsMemberFunction() {
+			return "", fmt.Errorf("%s must be called on an alert field", function)
+		}
+		field, err := celField(call.Target())
+		if err != nil {
+			return "", err
+		}
+		value, err := celLiteral(args[0])
+		if err != nil {
+			return "", err
+		}
+		pattern := fmt.Sprintf(celLikePatterns[function], likeEscaper.Replace(fmt.Sprint(value)))
+		return fmt.Sprintf(`%s like %s escape '\'`, field, t.param(field, pattern)), nil
+
+	default:
+		operator, ok := celSQLOperators[function]
+		if !ok {
+			return "", fmt.Errorf("%s can't be translated to SQL, only ==, !=, <, <=, >, >=, in, contains, startsWith and endsWith combined with &&, || and ! can", describeCELExpr(e))
+		}
+		field, err := celField(args[0])
+		if err != nil {
+			return "", err
+		}
+		if args[1].Kind() == ast.LiteralKind && args[1].AsLiteral() == types.NullValue {
+			switch function {
+			case operators.Equals:
+				return field + " is null", nil
+			case operators.NotEquals:
+				return field + " is not null", nil
+			}
+		}
+		value, err := celLiteral(args[1])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", field, operator, t.param(field, value)), nil
+	}
+}
+
+// param func adds the value as a parameter named after the field and returns its placeholder
+func (t *celSQLTranslator) param(field string, value interface{}) string {
+	name := sqlParamName.ReplaceAllString(field, "_")
+	t.counts[name]++
+	name = fmt.Sprintf("%s_%d", name, t.counts[name])
+	t.params[name] = value
+	return ":" + name
+}
+
+// celField func returns the alert field the expression refers to, nested fields like labels.team are joined with dots
+func celField(e ast.Expr) (string, error) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return e.AsIdent(), nil
+	case ast.SelectKind:
+		if e.AsSelect().IsTestOnly() {
+			break
+		}
+		operand, err := celField(e.AsSelect().Operand())
+		if err != nil {
+			return "", err
+		}
+		return operand + "." + e.AsSelect().FieldName(), nil
+	case ast.CallKind:
+		// labels["team"]
+		if call := e.AsCall(); call.FunctionName() == operators.Index {
+			operand, err := celField(call.Args()[0])
+			if err != nil {
+				return "", err
+			}
+			key, err := celLiteral(call.Args()[1])
+			if err != nil {
+				return "", err
+			}
+			if key, ok := key.(string); ok {
+				return operand + "." + key, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not an alert field, fields must be compared with literal values to be translated to SQL", describeCELExpr(e))
+}
+
+// celLiteral func returns the value of a literal expression
+func celLiteral(e ast.Expr) (interface{}, error) {
+	if e.Kind() != ast.LiteralKind || e.AsLiteral() == types.NullValue {
+		return nil, fmt.Errorf("%s is not a literal value, fields must be compared with literal values to be translated to SQL", describeCELExpr(e))
+	}
+	return e.AsLiteral().Value(), nil
+}
+
+// describeCELExpr func names the expression in errors
+func describeCELExpr(e ast.Expr) string {
+	switch e.Kind() {
+	case ast.CallKind:
+		if operator, ok := operators.FindReverse(e.AsCall().FunctionName()); ok {
+			return "operator " + operator
+		}
+		return "function " + e.AsCall().FunctionName()
+	case ast.IdentKind:
+		return "field " + e.AsIdent()
+	case ast.SelectKind:
+		if e.AsSelect().IsTestOnly() {
+			return "macro has"
+		}
+		if field, err := celField(e); err == nil {
+			return "field " + field
+		}
+		return "field " + e.AsSelect().FieldName()
+	case ast.LiteralKind:
+		return fmt.Sprintf("value %v", e.AsLiteral().Value())
+	case ast.ListKind:
+		return "list"
+	case ast.MapKind:
+		return "map"
+	case ast.ComprehensionKind:
+		return "macro"
+	}
+	return "expression"
+}
+
+// validateCELSQL func validates CEL queries keep also needs as SQL, like the queries of correlation rules
+func validateCELSQL(v interface{}, path cty.Path) diag.Diagnostics {
+	if diags := validateCELCondition(v, path); diags.HasError() {
+		return diags
+	}
+
+	if _, _, err := celToSQL(v.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Unsupported CEL query",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
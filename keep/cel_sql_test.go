@@ -0,0 +1,111 @@
+package keep
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCELToSQL(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		query  string
+		sql    string
+		params map[string]interface{}
+	}{
+		{
+			name:   "comparison",
+			query:  `severity == "critical"`,
+			sql:    `(severity = :severity_1)`,
+			params: map[string]interface{}{"severity_1": "critical"},
+		},
+		{
+			name:   "and with a nested or",
+			query:  `severity == "critical" && (source.contains("prometheus") || service != "db")`,
+			sql:    `(severity = :severity_1 and (source like :source_1 escape '\' or service != :service_1))`,
+			params: map[string]interface{}{"severity_1": "critical", "source_1": "%prometheus%", "service_1": "db"},
+		},
+		{
+			name:   "not",
+			query:  `!(status == "resolved")`,
+			sql:    `(NOT (status = :status_1))`,
+			params: map[string]interface{}{"status_1": "resolved"},
+		},
+		{
+			name:   "in",
+			query:  `severity in ["critical", "high"]`,
+			sql:    `(severity in (:severity_1, :severity_2))`,
+			params: map[string]interface{}{"severity_1": "critical", "severity_2": "high"},
+		},
+		{
+			name:   "null comparisons",
+			query:  `labels.team == null || labels.owner != null`,
+			sql:    `(labels.team is null or labels.owner is not null)`,
+			params: map[string]interface{}{},
+		},
+		{
+			name:   "labels index",
+			query:  `labels["team"] == "payments" && labels.team != "search"`,
+			sql:    `(labels.team = :labels_team_1 and labels.team != :labels_team_2)`,
+			params: map[string]interface{}{"labels_team_1": "payments", "labels_team_2": "search"},
+		},
+		{
+			name:   "param numbering per field",
+			query:  `severity == "critical" || severity == "high" || service == "db"`,
+			sql:    `((severity = :severity_1 or severity = :severity_2) or service = :service_1)`,
+			params: map[string]interface{}{"severity_1": "critical", "severity_2": "high", "service_1": "db"},
+		},
+		{
+			name:   "ordering with numbers",
+			query:  `labels.count > 5 && labels.ratio <= 0.5`,
+			sql:    `(labels.count > :labels_count_1 and labels.ratio <= :labels_ratio_1)`,
+			params: map[string]interface{}{"labels_count_1": int64(5), "labels_ratio_1": 0.5},
+		},
+		{
+			name:   "like patterns",
+			query:  `name.startsWith("cpu") && name.endsWith("high")`,
+			sql:    `(name like :name_1 escape '\' and name like :name_2 escape '\')`,
+			params: map[string]interface{}{"name_1": "cpu%", "name_2": "%high"},
+		},
+		{
+			name:   "like special characters are escaped",
+			query:  `message.contains("50%_off\\")`,
+			sql:    `(message like :message_1 escape '\')`,
+			params: map[string]interface{}{"message_1": `%50\%\_off\\%`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, params, err := celToSQL(tc.query)
+			if err != nil {
+				t.Fatalf("cannot translate %q: %s", tc.query, err)
+			}
+			if sql != tc.sql {
+				t.Errorf("expected sql %s, got %s", tc.sql, sql)
+			}
+			if !reflect.DeepEqual(params, tc.params) {
+				t.Errorf("expected params %v, got %v", tc.params, params)
+			}
+		})
+	}
+}
+
+func TestCELToSQLRejectsUnsupportedQueries(t *testing.T) {
+	for name, query := range map[string]string{
+		"invalid CEL":              `severity ==`,
+		"field compared to field":  `severity == service`,
+		"literal on the left":      `"critical" == severity`,
+		"function":                 `size(source) > 1`,
+		"regex":                    `severity.matches("crit.*")`,
+		"macro":                    `labels.exists(k, k == "team")`,
+		"has macro":                `has(labels.team)`,
+		"field in a list":          `severity in [service]`,
+		"bool field alone":         `dismissed`,
+		"field in a field":         `"team" in labels`,
+		"contains with a variable": `source.contains(service)`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if sql, _, err := celToSQL(query); err == nil {
+				t.Errorf("expected %q to be rejected, got %s", query, sql)
+			}
+		})
+	}
+}
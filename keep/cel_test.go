@@ -0,0 +1,109 @@
+package keep
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestCompileCELCondition(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		condition string
+		valid     bool
+	}{
+		{name: "string comparison", condition: `severity == "critical"`, valid: true},
+		{name: "source as a list", condition: `source.contains("prometheus")`, valid: true},
+		{name: "source as a string", condition: `source == "prometheus"`, valid: true},
+		{name: "labels", condition: `labels.team == "payments" && labels["env"] != "dev"`, valid: true},
+		{name: "bool field", condition: `!dismissed && isNoisy`, valid: true},
+		{name: "list field", condition: `"service" in enriched_fields`, valid: true},
+		{name: "string functions", condition: `name.startsWith("cpu") || message.matches("^disk")`, valid: true},
+		{name: "dynamic result", condition: `labels.active`, valid: true},
+		{name: "syntax error", condition: `severity == `},
+		{name: "unknown field", condition: `priority == "high"`},
+		{name: "mismatched types", condition: `severity == 1`},
+		{name: "not a bool", condition: `severity`},
+		{name: "string function on a bool", condition: `pushed.startsWith("t")`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := compileCELCondition(tc.condition)
+			if tc.valid && err != nil {
+				t.Errorf("expected %q to be valid, got %s", tc.condition, err)
+			}
+			if !tc.valid && err == nil {
+				t.Errorf("expected %q to be rejected", tc.condition)
+			}
+		})
+	}
+}
+
+func TestValidateCELCondition(t *testing.T) {
+	// empty conditions match every alert
+	if diags := validateCELCondition("", cty.GetAttrPath("condition")); len(diags) != 0 {
+		t.Errorf("expected an empty condition to be valid, got %v", diags)
+	}
+
+	diags := validateCELCondition(`severity == 1`, cty.GetAttrPath("condition"))
+	if len(diags) != 1 || diags[0].Summary != "Invalid CEL condition" || !diags[0].AttributePath.Equals(cty.GetAttrPath("condition")) {
+		t.Errorf("expected an invalid CEL condition error on the condition, got %v", diags)
+	}
+}
+
+func TestEvalCELCondition(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		condition string
+		alert     map[string]interface{}
+		expected  bool
+	}{
+		{
+			name:      "matching alert",
+			condition: `severity == "critical" && source.contains("prometheus")`,
+			alert:     map[string]interface{}{"severity": "critical", "source": []interface{}{"prometheus"}},
+			expected:  true,
+		},
+		{
+			name:      "not matching alert",
+			condition: `severity == "critical"`,
+			alert:     map[string]interface{}{"severity": "warning"},
+			expected:  false,
+		},
+		{
+			name:      "missing fields get the defaults of keep",
+			condition: `service == "" && !dismissed && size(source) == 0 && size(labels) == 0`,
+			alert:     map[string]interface{}{},
+			expected:  true,
+		},
+		{
+			name:      "labels",
+			condition: `labels.team == "payments"`,
+			alert:     map[string]interface{}{"labels": map[string]interface{}{"team": "payments"}},
+			expected:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ast, err := compileCELCondition(tc.condition)
+			if err != nil {
+				t.Fatalf("cannot compile condition: %s", err)
+			}
+
+			matched, err := evalCELCondition(ast, tc.alert)
+			if err != nil {
+				t.Fatalf("cannot evaluate condition: %s", err)
+			}
+			if matched != tc.expected {
+				t.Errorf("expected %q to evaluate to %t, got %t", tc.condition, tc.expected, matched)
+			}
+		})
+	}
+
+	// labels are dynamic, values that aren't bools are only rejected when the condition is evaluated
+	ast, err := compileCELCondition(`labels.active`)
+	if err != nil {
+		t.Fatalf("cannot compile condition: %s", err)
+	}
+	if _, err := evalCELCondition(ast, map[string]interface{}{"labels": map[string]interface{}{"active": "yes"}}); err == nil {
+		t.Errorf("expected an error for a condition evaluating to a string")
+	}
+}
@@ -1,6 +1,7 @@
 package keep
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -17,6 +18,10 @@ type Client struct {
 	HostURL    string
 	HTTPClient *http.Client
 	ApiKey     string
+	// MaxRequestSize is the maximum size of request bodies in bytes, 0 means no limit
+	MaxRequestSize int64
+	// CompressRequests compresses request bodies with gzip
+	CompressRequests bool
 }
 
 // NewClient func creates new client
@@ -32,25 +37,117 @@ func NewClient(hostUrl string, apiKey string, timeout time.Duration) *Client {
 
 // doReq func does the api requests
 func (c *Client) doReq(req *http.Request) ([]byte, error) {
-	req.Header.Add("X-API-KEY", c.ApiKey)
+	return c.do(c.HTTPClient, req)
+}
+
+// doLongReq func does the api requests that may take longer than the client timeout, e.g. large uploads.
+// They are bounded by the context of the request instead.
+func (c *Client) doLongReq(req *http.Request) ([]byte, error) {
+	httpClient := *c.HTTPClient
+	httpClient.Timeout = 0
+	return c.do(&httpClient, req)
+}
+
+// doStreamReq func does the api requests with large responses, the response body is returned unread to be decoded
+// while it is received and must be closed. Like doLongReq they are bounded by the context of the request.
+func (c *Client) doStreamReq(req *http.Request) (io.ReadCloser, error) {
+	httpClient := *c.HTTPClient
+	httpClient.Timeout = 0
+
+	res, err := c.send(&httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
 
-	res, err := c.HTTPClient.Do(req)
+func (c *Client) do(httpClient *http.Client, req *http.Request) ([]byte, error) {
+	res, err := c.send(httpClient, req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
+	return io.ReadAll(res.Body)
+}
+
+// send func sends the request and returns the response when its status is ok
+func (c *Client) send(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	req.Header.Add("X-API-KEY", c.ApiKey)
+
+	if req.Body != nil {
+		if err := c.prepareBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	statusOk := res.StatusCode >= 200 && res.StatusCode < 300
 	if !statusOk {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
 		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
 	}
 
-	return body, err
+	return res, nil
+}
+
+// prepareBody func compresses the request body and enforces the request size limit
+func (c *Client) prepareBody(req *http.Request) error {
+	if c.CompressRequests {
+		body := req.Body
+		pr, pw := io.Pipe()
+		go func() {
+			gz := gzip.NewWriter(pw)
+			_, err := io.Copy(gz, body)
+			if err == nil {
+				err = gz.Close()
+			}
+			body.Close()
+			pw.CloseWithError(err)
+		}()
+
+		req.Body = pr
+		req.GetBody = nil
+		// the compressed size is unknown, the body is sent chunked
+		req.ContentLength = 0
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	if c.MaxRequestSize > 0 {
+		if req.ContentLength > c.MaxRequestSize {
+			req.Body.Close()
+			return requestTooLargeError(c.MaxRequestSize)
+		}
+		req.Body = &limitedBody{ReadCloser: req.Body, remaining: c.MaxRequestSize, limit: c.MaxRequestSize}
+	}
+
+	return nil
+}
+
+// limitedBody fails the request once more than limit bytes of the body are sent
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, requestTooLargeError(b.limit)
+	}
+	return n, err
+}
+
+func requestTooLargeError(limit int64) error {
+	return fmt.Errorf("request body exceeds max_request_size of %d bytes, increase max_request_size or split the request", limit)
 }
 
 func ClientConfigurer(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -64,5 +161,9 @@ func ClientConfigurer(ctx context.Context, d *schema.ResourceData) (interface{},
 		return nil, diag.Errorf("timeout was not a valid duration: %s", err.Error())
 	}
 
-	return NewClient(host.String(), d.Get("api_key").(string), timeout), nil
+	client := NewClient(host.String(), d.Get("api_key").(string), timeout)
+	client.MaxRequestSize = int64(d.Get("max_request_size").(int))
+	client.CompressRequests = d.Get("compress_requests").(bool)
+
+	return client, nil
 }
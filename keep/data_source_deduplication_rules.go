@@ -0,0 +1,250 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+type DeduplicationRule struct {
+	ID                string                      `json:"id"`
+	Name              string                      `json:"name"`
+	Description       string                      `json:"description"`
+	Default           bool                        `json:"default"`
+	ProviderID        string                      `json:"provider_id"`
+	ProviderType      string                      `json:"provider_type"`
+	FingerprintFields []string                    `json:"fingerprint_fields"`
+	IgnoreFields      []string                    `json:"ignore_fields"`
+	FullDeduplication bool                        `json:"full_deduplication"`
+	Ingested          int                         `json:"ingested"`
+	DedupRatio        float64                     `json:"dedup_ratio"`
+	Distribution      []DeduplicationDistribution `json:"distribution"`
+	CreatedBy         string                      `json:"created_by"`
+	LastUpdated       string                      `json:"last_updated"`
+	LastUpdatedBy     string                      `json:"last_updated_by"`
+}
+
+type DeduplicationDistribution struct {
+	Hour   int `json:"hour"`
+	Number int `json:"number"`
+}
+
+// getDeduplicationRules func fetches the deduplication rules from keep, including the default rules of the providers
+func getDeduplicationRules(client *Client) ([]DeduplicationRule, error) {
+	// create new request
+	req, err := http.NewRequest("GET", client.HostURL+"/deduplications", nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	body, err := client.doReq(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response []DeduplicationRule
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	return response, nil
+}
+
+func dataSourceDeduplicationRules() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceReadDeduplicationRules,
+		Description: "Lists the deduplication rules with the number of alerts they ingested and deduplicated.",
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Filter deduplication rules by a regex matching the name",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsValidRegExp),
+			},
+			"provider_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter deduplication rules by provider type",
+			},
+			"provider_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter deduplication rules by provider id",
+			},
+			"rules": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of deduplication rules matching the filters",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the deduplication rule",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the deduplication rule",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Description of the deduplication rule",
+						},
+						"default": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the rule is the default rule keep applies to the provider",
+						},
+						"provider_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the provider the rule deduplicates the alerts of",
+						},
+						"provider_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the provider the rule deduplicates the alerts of",
+						},
+						"fingerprint_fields": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Fields the fingerprint of the alerts is calculated from",
+						},
+						"ignore_fields": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Fields ignored when comparing alerts with full deduplication",
+						},
+						"full_deduplication": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether alerts equal to a previous alert are dropped",
+						},
+						"ingested": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of alerts the rule ingested",
+						},
+						"dedup_ratio": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "Percentage of the ingested alerts that were deduplicated",
+						},
+						"distribution": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Number of alerts deduplicated by the hour of the last 24 hours",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"hour": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "Hour of the day",
+									},
+									"number": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "Number of alerts deduplicated in the hour",
+									},
+								},
+							},
+						},
+						"created_by": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Creator of the deduplication rule",
+						},
+						"last_updated": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Last update time of the deduplication rule",
+						},
+						"last_updated_by": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Last updater of the deduplication rule",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceReadDeduplicationRules(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	providerType := d.Get("provider_type").(string)
+	providerID := d.Get("provider_id").(string)
+
+	var nameRegex *regexp.Regexp
+	if v := d.Get("name_regex").(string); v != "" {
+		nameRegex = regexp.MustCompile(v)
+	}
+
+	response, err := getDeduplicationRules(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rules := make([]map[string]interface{}, 0, len(response))
+	for _, rule := range response {
+		if nameRegex != nil && !nameRegex.MatchString(rule.Name) {
+			continue
+		}
+		if providerType != "" && rule.ProviderType != providerType {
+			continue
+		}
+		if providerID != "" && rule.ProviderID != providerID {
+			continue
+		}
+
+		distribution := make([]map[string]interface{}, len(rule.Distribution))
+		for i, hour := range rule.Distribution {
+			distribution[i] = map[string]interface{}{
+				"hour":   hour.Hour,
+				"number": hour.Number,
+			}
+		}
+
+		rules = append(rules, map[string]interface{}{
+			"id":                 rule.ID,
+			"name":               rule.Name,
+			"description":        rule.Description,
+			"default":            rule.Default,
+			"provider_type":      rule.ProviderType,
+			"provider_id":        rule.ProviderID,
+			"fingerprint_fields": rule.FingerprintFields,
+			"ignore_fields":      rule.IgnoreFields,
+			"full_deduplication": rule.FullDeduplication,
+			"ingested":           rule.Ingested,
+			"dedup_ratio":        rule.DedupRatio,
+			"distribution":       distribution,
+			"created_by":         rule.CreatedBy,
+			"last_updated":       rule.LastUpdated,
+			"last_updated_by":    rule.LastUpdatedBy,
+		})
+	}
+
+	if err := d.Set("rules", rules); err != nil {
+		return diag.Errorf("cannot set rules: %s", err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
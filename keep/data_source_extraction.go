@@ -0,0 +1,164 @@
+package keep
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cast"
+)
+
+// extractionDataSourceSchema func returns the attributes of extraction rules read by the extraction data sources
+func extractionDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "ID of the extraction",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Name of the extraction",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Description of the extraction",
+		},
+		"priority": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Priority of the extraction",
+		},
+		"attribute": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Attribute of the extraction",
+		},
+		"condition": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "CEL condition alerts must match for the extraction to run",
+		},
+		"disabled": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the extraction is disabled",
+		},
+		"regex": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Regex of the extraction",
+		},
+		"pre": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Pre of the extraction",
+		},
+		"extracted_fields": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Names of the named groups of the regex, the attributes the extraction adds to alerts",
+		},
+		"created_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Creation time of the extraction",
+		},
+		"created_by": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Creator of the extraction",
+		},
+		"updated_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Time of the last update of the extraction",
+		},
+	}
+}
+
+func dataSourceExtraction() *schema.Resource {
+	extractionSchema := extractionDataSourceSchema()
+	extractionSchema["id"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		Description:  "ID of the extraction, one of `id` or `name` must be specified",
+		ExactlyOneOf: []string{"id", "name"},
+	}
+	extractionSchema["name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		Description:  "Name of the extraction, one of `id` or `name` must be specified",
+		ExactlyOneOf: []string{"id", "name"},
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceReadExtraction,
+		Schema:      extractionSchema,
+	}
+}
+
+func dataSourceReadExtraction(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	id := d.Get("id").(int)
+	name := d.Get("name").(string)
+
+	extractions, err := getExtractions(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var found []map[string]interface{}
+	for _, extraction := range extractions {
+		if (id != 0 && cast.ToInt(extraction["id"]) == id) || (id == 0 && cast.ToString(extraction["name"]) == name) {
+			found = append(found, extraction)
+		}
+	}
+
+	switch {
+	case len(found) == 0 && id != 0:
+		return diag.Errorf("extraction with id %d not found", id)
+	case len(found) == 0:
+		return diag.Errorf("extraction with name %q not found", name)
+	case len(found) > 1:
+		// names are not unique in keep
+		return diag.Errorf("found %d extractions with name %q, use id to select one of them", len(found), name)
+	}
+
+	extraction := extractionToMap(found[0])
+	d.SetId(strconv.Itoa(extraction["id"].(int)))
+	for key, value := range extraction {
+		if err := d.Set(key, value); err != nil {
+			return diag.Errorf("cannot set %s: %s", key, err)
+		}
+	}
+
+	return nil
+}
+
+// extractionToMap func converts the extraction as keep returns it to the attributes of the extraction data sources
+func extractionToMap(extraction map[string]interface{}) map[string]interface{} {
+	groups, _ := scanPythonRegex(cast.ToString(extraction["regex"]))
+	return map[string]interface{}{
+		"id":               cast.ToInt(extraction["id"]),
+		"name":             cast.ToString(extraction["name"]),
+		"description":      cast.ToString(extraction["description"]),
+		"priority":         cast.ToInt(extraction["priority"]),
+		"attribute":        cast.ToString(extraction["attribute"]),
+		"condition":        cast.ToString(extraction["condition"]),
+		"disabled":         cast.ToBool(extraction["disabled"]),
+		"regex":            cast.ToString(extraction["regex"]),
+		"pre":              cast.ToBool(extraction["pre"]),
+		"extracted_fields": groups,
+		"created_at":       cast.ToString(extraction["created_at"]),
+		"created_by":       cast.ToString(extraction["created_by"]),
+		"updated_at":       cast.ToString(extraction["updated_at"]),
+	}
+}
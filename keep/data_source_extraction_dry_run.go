@@ -0,0 +1,191 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/spf13/cast"
+)
+
+// dataSourceExtractionDryRun func returns the keep_extraction_test data source, it runs an extraction against sample alerts
+// locally the way keep does, without calling keep
+func dataSourceExtractionDryRun() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceReadExtractionDryRun,
+		Description: "Runs an extraction against sample alerts locally, the way keep runs it against incoming alerts. " +
+			"The regex is evaluated with go's regexp package, regexes using constructs only python supports can't be tested.",
+		Schema: map[string]*schema.Schema{
+			"attribute": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Attribute of the alert the regex is matched against, nested attributes are separated by dots like `labels.instance`",
+			},
+			"regex": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "Regex of the extraction, it must match at the beginning of the attribute like python's `re.match`",
+				ValidateDiagFunc: validatePythonRegex,
+			},
+			"condition": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "CEL condition alerts must match for the extraction to run",
+				ValidateDiagFunc: validateCELCondition,
+			},
+			"alerts": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsJSON),
+				},
+				Description: "Sample alerts as JSON documents, e.g. built with `jsonencode`",
+			},
+			"results": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Result of the extraction for every alert, in the order of `alerts`",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"condition_matched": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the alert matches the condition",
+						},
+						"matched": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the regex matched the attribute, i.e. the extraction ran",
+						},
+						"extracted": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Fields extracted from the alert by the named groups of the regex",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceReadExtractionDryRun(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	pattern := d.Get("regex").(string)
+	condition := d.Get("condition").(string)
+	attribute := extractionAttribute(d.Get("attribute").(string))
+
+	_, constructs := scanPythonRegex(pattern)
+	for _, construct := range constructs {
+		if construct.pythonOnly {
+			return diag.Errorf("cannot test regex locally, %s is only supported by python: %s", construct.text, construct.hint)
+		}
+	}
+
+	// \Z of python is \z in go
+	regex, err := regexp.Compile(strings.ReplaceAll(pattern, `\Z`, `\z`))
+	if err != nil {
+		return diag.Errorf("cannot compile regex: %s", err)
+	}
+
+	var ast *cel.Ast
+	if condition != "" {
+		ast, err = compileCELCondition(condition)
+		if err != nil {
+			return diag.Errorf("cannot compile condition: %s", err)
+		}
+	}
+
+	alerts := d.Get("alerts").([]interface{})
+	results := make([]map[string]interface{}, len(alerts))
+	for i, alertJSON := range alerts {
+		var alert map[string]interface{}
+		if err := json.Unmarshal([]byte(cast.ToString(alertJSON)), &alert); err != nil {
+			return diag.Errorf("cannot unmarshal alert %d: %s", i, err)
+		}
+
+		result := map[string]interface{}{
+			"condition_matched": true,
+			"matched":           false,
+			"extracted":         map[string]string{},
+		}
+		results[i] = result
+
+		if ast != nil {
+			matched, err := evalCELCondition(ast, alert)
+			if err != nil {
+				return diag.Errorf("cannot evaluate condition for alert %d: %s", i, err)
+			}
+			result["condition_matched"] = matched
+			if !matched {
+				continue
+			}
+		}
+
+		value, ok := nestedAttribute(alert, attribute)
+		if !ok || value == "" {
+			// keep skips alerts without the attribute
+			continue
+		}
+
+		// keep matches the regex at the beginning of the attribute, the leftmost match starts there if any does
+		match := regex.FindStringSubmatchIndex(value)
+		if match == nil || match[0] != 0 {
+			continue
+		}
+
+		extracted := make(map[string]string)
+		for group, name := range regex.SubexpNames() {
+			// groups that didn't participate in the match are not extracted
+			if name == "" || match[2*group] < 0 {
+				continue
+			}
+			extracted[name] = value[match[2*group]:match[2*group+1]]
+		}
+		result["matched"] = true
+		result["extracted"] = extracted
+	}
+
+	if err := d.Set("results", results); err != nil {
+		return diag.Errorf("cannot set results: %s", err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
+
+// extractionAttribute func returns the attribute of an extraction without the {{ }} keep allows around it
+func extractionAttribute(attribute string) string {
+	if strings.HasPrefix(attribute, "{{") && strings.HasSuffix(attribute, "}}") {
+		return strings.TrimSpace(attribute[2 : len(attribute)-2])
+	}
+	return attribute
+}
+
+// nestedAttribute func returns the value of a dotted attribute of the alert, only scalar values can be matched
+func nestedAttribute(alert map[string]interface{}, attribute string) (string, bool) {
+	var value interface{} = alert
+	for _, key := range strings.Split(attribute, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if value, ok = object[key]; !ok {
+			return "", false
+		}
+	}
+
+	switch value.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		return "", false
+	}
+	return cast.ToString(value), true
+}
@@ -0,0 +1,160 @@
+package keep
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceReadExtractionDryRun(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		attribute string
+		regex     string
+		condition string
+		alert     string
+		// conditionMatched is true unless set otherwise
+		conditionMatched *bool
+		matched          bool
+		extracted        map[string]interface{}
+	}{
+		{
+			name:      "named groups",
+			attribute: "labels.instance",
+			regex:     `(?P<host>[a-z0-9-]+):(?P<port>\d+)`,
+			alert:     `{"labels": {"instance": "web-1:9100"}}`,
+			matched:   true,
+			extracted: map[string]interface{}{"host": "web-1", "port": "9100"},
+		},
+		{
+			name:      "match is anchored at the beginning",
+			attribute: "name",
+			regex:     `(?P<code>\d+)`,
+			alert:     `{"name": "error 500"}`,
+			matched:   false,
+			extracted: map[string]interface{}{},
+		},
+		{
+			name:      "match doesn't need to reach the end",
+			attribute: "name",
+			regex:     `(?P<code>\d+)`,
+			alert:     `{"name": "500 error"}`,
+			matched:   true,
+			extracted: map[string]interface{}{"code": "500"},
+		},
+		{
+			name:      "later match is ignored when the first one doesn't start at the beginning",
+			attribute: "name",
+			regex:     `x*(?P<word>[a-z]+)`,
+			alert:     `{"name": "-abc"}`,
+			matched:   false,
+			extracted: map[string]interface{}{},
+		},
+		{
+			name:      "optional group that didn't participate",
+			attribute: "name",
+			regex:     `(?P<service>[a-z]+)(?:-(?P<env>prod|dev))?`,
+			alert:     `{"name": "checkout"}`,
+			matched:   true,
+			extracted: map[string]interface{}{"service": "checkout"},
+		},
+		{
+			name:      "attribute in braces",
+			attribute: "{{ service }}",
+			regex:     `(?P<team>[a-z]+)-`,
+			alert:     `{"service": "payments-api"}`,
+			matched:   true,
+			extracted: map[string]interface{}{"team": "payments"},
+		},
+		{
+			name:      "end of string like python",
+			attribute: "service",
+			regex:     `(?P<name>[a-z]+)\Z`,
+			alert:     `{"service": "payments"}`,
+			matched:   true,
+			extracted: map[string]interface{}{"name": "payments"},
+		},
+		{
+			name:      "missing attribute",
+			attribute: "labels.instance",
+			regex:     `(?P<host>.+)`,
+			alert:     `{"labels": {}}`,
+			matched:   false,
+			extracted: map[string]interface{}{},
+		},
+		{
+			name:      "attribute that isn't a scalar",
+			attribute: "labels",
+			regex:     `(?P<host>.+)`,
+			alert:     `{"labels": {"instance": "web-1"}}`,
+			matched:   false,
+			extracted: map[string]interface{}{},
+		},
+		{
+			name:      "condition matched",
+			attribute: "name",
+			regex:     `(?P<code>\d+)`,
+			condition: `severity == "critical"`,
+			alert:     `{"name": "500", "severity": "critical"}`,
+			matched:   true,
+			extracted: map[string]interface{}{"code": "500"},
+		},
+		{
+			name:             "condition not matched",
+			attribute:        "name",
+			regex:            `(?P<code>\d+)`,
+			condition:        `severity == "critical"`,
+			alert:            `{"name": "500", "severity": "warning"}`,
+			conditionMatched: new(bool),
+			matched:          false,
+			extracted:        map[string]interface{}{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := dataSourceExtractionDryRun()
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"attribute": tc.attribute,
+				"regex":     tc.regex,
+				"condition": tc.condition,
+				"alerts":    []interface{}{tc.alert},
+			})
+
+			if diags := dataSourceReadExtractionDryRun(context.Background(), d, nil); diags.HasError() {
+				t.Fatalf("cannot run extraction: %v", diags)
+			}
+
+			results := d.Get("results").([]interface{})
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results))
+			}
+			result := results[0].(map[string]interface{})
+
+			conditionMatched := tc.conditionMatched == nil || *tc.conditionMatched
+			if result["condition_matched"] != conditionMatched {
+				t.Errorf("expected condition_matched to be %t, got %v", conditionMatched, result["condition_matched"])
+			}
+			if result["matched"] != tc.matched {
+				t.Errorf("expected matched to be %t, got %v", tc.matched, result["matched"])
+			}
+			if !reflect.DeepEqual(result["extracted"], tc.extracted) {
+				t.Errorf("expected extracted %v, got %v", tc.extracted, result["extracted"])
+			}
+		})
+	}
+}
+
+func TestDataSourceReadExtractionDryRunPythonOnlyRegex(t *testing.T) {
+	r := dataSourceExtractionDryRun()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"attribute": "name",
+		"regex":     `(?P<word>\w+)(?=:)`,
+		"alerts":    []interface{}{`{"name": "cpu: high"}`},
+	})
+
+	// lookaheads can't be evaluated with go's regexp package
+	if diags := dataSourceReadExtractionDryRun(context.Background(), d, nil); !diags.HasError() {
+		t.Errorf("expected an error for a regex only python supports")
+	}
+}
@@ -0,0 +1,85 @@
+package keep
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceExtractions() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceReadExtractions,
+		Schema: map[string]*schema.Schema{
+			"attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter extractions by attribute",
+			},
+			"disabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Filter extractions by whether they are disabled",
+			},
+			"pre": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Filter extractions by pre",
+			},
+			"extractions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of extractions matching the filters, in the order keep runs them",
+				Elem: &schema.Resource{
+					Schema: extractionDataSourceSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceReadExtractions(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	attribute := d.Get("attribute").(string)
+	// unset bools read as false, the raw config tells them apart from false
+	config := d.GetRawConfig()
+	filterDisabled := !config.GetAttr("disabled").IsNull()
+	filterPre := !config.GetAttr("pre").IsNull()
+
+	response, err := getExtractions(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	extractions := make([]map[string]interface{}, 0, len(response))
+	for _, item := range response {
+		extraction := extractionToMap(item)
+		if attribute != "" && extraction["attribute"] != attribute {
+			continue
+		}
+		if filterDisabled && extraction["disabled"] != d.Get("disabled").(bool) {
+			continue
+		}
+		if filterPre && extraction["pre"] != d.Get("pre").(bool) {
+			continue
+		}
+
+		extractions = append(extractions, extraction)
+	}
+
+	// higher priorities run first
+	sort.SliceStable(extractions, func(i, j int) bool {
+		return extractions[i]["priority"].(int) > extractions[j]["priority"].(int)
+	})
+
+	if err := d.Set("extractions", extractions); err != nil {
+		return diag.Errorf("cannot set extractions: %s", err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/spf13/cast"
+)
+
+type InstalledProvider struct {
+	ID                string                 `json:"id"`
+	Type              string                 `json:"type"`
+	Details           map[string]interface{} `json:"details"`
+	Config            map[string]interface{} `json:"config"`
+	LastAlertReceived string                 `json:"last_alert_received"`
+	InstalledBy       string                 `json:"installed_by"`
+	InstallationTime  string                 `json:"installation_time"`
+	ValidatedScopes   map[string]interface{} `json:"validatedScopes"`
+	PullingAvailable  bool                   `json:"pulling_available"`
+	PullingEnabled    bool                   `json:"pulling_enabled"`
+	Provisioned       bool                   `json:"provisioned"`
+	SupportsWebhook   bool                   `json:"supports_webhook"`
+}
+
+// Name returns the name the provider was installed with
+func (p InstalledProvider) Name() string {
+	return cast.ToString(p.Details["name"])
+}
+
+// InstallationSource returns how the provider was installed, either provisioned or manual
+func (p InstalledProvider) InstallationSource() string {
+	if p.Provisioned {
+		return "provisioned"
+	}
+	return "manual"
+}
+
+// Scopes returns the validated scopes as strings, "true" for valid scopes or the validation error otherwise
+func (p InstalledProvider) Scopes() map[string]string {
+	return scopesToStrings(p.ValidatedScopes)
+}
+
+// IsSensitive returns whether the given config field of the provider is a secret
+func (p InstalledProvider) IsSensitive(key string) bool {
+	field, ok := p.Config[key].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	return cast.ToBool(field["sensitive"])
+}
+
+// getInstalledProviders func fetches the installed providers from keep
+func getInstalledProviders(client *Client) ([]InstalledProvider, error) {
+	// create new request
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/providers/export", client.HostURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	body, err := client.doReq(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response struct {
+		InstalledProviders []InstalledProvider `json:"installed_providers"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	return response.InstalledProviders, nil
+}
+
+func dataSourceInstalledProviders() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceReadInstalledProviders,
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter installed providers by type",
+			},
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Filter installed providers by exact name",
+				ConflictsWith: []string{"name_regex"},
+			},
+			"name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Filter installed providers by a regex matching the name",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsValidRegExp),
+				ConflictsWith:    []string{"name"},
+			},
+			"installation_source": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Filter installed providers by installation source, one of `provisioned` or `manual`",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"provisioned", "manual"}, false)),
+			},
+			"providers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of installed providers matching the filters",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the installed provider",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the installed provider",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the installed provider",
+						},
+						"last_alert_received": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time of the last alert received from the provider",
+						},
+						"installed_by": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "User who installed the provider",
+						},
+						"installation_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Installation time of the provider",
+						},
+						"installation_source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Installation source of the provider, either `provisioned` or `manual`",
+						},
+						"validated_scopes": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Scope validation results, `true` for valid scopes or the validation error otherwise",
+						},
+						"pulling_available": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the provider supports pulling alerts",
+						},
+						"pulling_enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether alert pulling is enabled for the provider",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceReadInstalledProviders(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	providerType := d.Get("type").(string)
+	name := d.Get("name").(string)
+	source := d.Get("installation_source").(string)
+
+	var nameRegex *regexp.Regexp
+	if v := d.Get("name_regex").(string); v != "" {
+		nameRegex = regexp.MustCompile(v)
+	}
+
+	installedProviders, err := getInstalledProviders(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	providers := make([]map[string]interface{}, 0, len(installedProviders))
+	for _, provider := range installedProviders {
+		if providerType != "" && provider.Type != providerType {
+			continue
+		}
+		if name != "" && provider.Name() != name {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(provider.Name()) {
+			continue
+		}
+		if source != "" && provider.InstallationSource() != source {
+			continue
+		}
+
+		providers = append(providers, map[string]interface{}{
+			"id":                  provider.ID,
+			"type":                provider.Type,
+			"name":                provider.Name(),
+			"last_alert_received": provider.LastAlertReceived,
+			"installed_by":        provider.InstalledBy,
+			"installation_time":   provider.InstallationTime,
+			"installation_source": provider.InstallationSource(),
+			"validated_scopes":    provider.Scopes(),
+			"pulling_available":   provider.PullingAvailable,
+			"pulling_enabled":     provider.PullingEnabled,
+		})
+	}
+
+	if err := d.Set("providers", providers); err != nil {
+		return diag.Errorf("cannot set providers: %s", err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
@@ -3,10 +3,13 @@ package keep
 import (
 	"context"
 	"encoding/json"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"fmt"
 	"net/http"
 	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cast"
 )
 
 type Mapping struct {
@@ -14,10 +17,166 @@ type Mapping struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
 	FileName    string   `json:"file_name"`
+	Priority    int      `json:"priority"`
+	Type        string   `json:"type"`
 	Matchers    []string `json:"matchers"`
 	Attributes  []string `json:"attributes"`
 	CreatedAt   string   `json:"created_at"`
 	CreatedBy   string   `json:"created_by"`
+	UpdatedAt   string   `json:"last_updated_at"`
+
+	// the rows are counted while they are decoded instead of being kept, hasRows is false when keep returns none
+	rowCount int
+	hasRows  bool
+}
+
+// RowCount returns the number of rows of the mapping, topology mappings have none
+func (m Mapping) RowCount() int {
+	return m.rowCount
+}
+
+// mappingRowsHandler returns the func the rows of a mapping are passed to while they are decoded, or nil to only count
+// them. It gets the position of the mapping in the list and the fields keep sent before its rows.
+type mappingRowsHandler func(index int, mapping Mapping) func(row map[string]string)
+
+// getMappings func fetches the mappings from keep. Keep has no endpoint returning a single mapping and mappings can have
+// a lot of rows, so the response is decoded while it is received and the rows are passed to onRows instead of being kept.
+func getMappings(ctx context.Context, client *Client, onRows mappingRowsHandler) ([]Mapping, error) {
+	// create new request
+	req, err := http.NewRequestWithContext(ctx, "GET", client.HostURL+"/mapping/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	body, err := client.doStreamReq(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send request: %s", err)
+	}
+	defer body.Close()
+
+	// unmarshal response
+	mappings, err := decodeMappings(json.NewDecoder(body), onRows)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	return mappings, nil
+}
+
+// decodeMappings func decodes the list of mappings one mapping at a time
+func decodeMappings(decoder *json.Decoder, onRows mappingRowsHandler) ([]Mapping, error) {
+	decoder.UseNumber()
+
+	if err := expectDelim(decoder, '['); err != nil {
+		return nil, err
+	}
+
+	mappings := make([]Mapping, 0)
+	for decoder.More() {
+		mapping, err := decodeMapping(decoder, len(mappings), onRows)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %d: %s", len(mappings)+1, err)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, expectDelim(decoder, ']')
+}
+
+// decodeMapping func decodes a mapping, its rows are decoded one by one
+func decodeMapping(decoder *json.Decoder, index int, onRows mappingRowsHandler) (Mapping, error) {
+	var mapping Mapping
+	if err := expectDelim(decoder, '{'); err != nil {
+		return mapping, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return mapping, err
+		}
+		key, _ := token.(string)
+
+		if key != "rows" {
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return mapping, err
+			}
+			fields[key] = value
+			continue
+		}
+
+		var onRow func(row map[string]string)
+		if onRows != nil {
+			// the handler gets the fields sent before the rows
+			if err := unmarshalMappingFields(fields, &mapping); err != nil {
+				return mapping, err
+			}
+			onRow = onRows(index, mapping)
+		}
+		if err := decodeMappingRows(decoder, &mapping, onRow); err != nil {
+			return mapping, fmt.Errorf("rows: %s", err)
+		}
+	}
+
+	if err := expectDelim(decoder, '}'); err != nil {
+		return mapping, err
+	}
+
+	return mapping, unmarshalMappingFields(fields, &mapping)
+}
+
+// decodeMappingRows func decodes the rows of the mapping one by one, keep returns null instead of the rows of some mappings
+func decodeMappingRows(decoder *json.Decoder, mapping *Mapping, onRow func(row map[string]string)) error {
+	token, err := decoder.Token()
+	if err != nil || token == nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a list of rows")
+	}
+	mapping.hasRows = true
+
+	for decoder.More() {
+		var remoteRow map[string]interface{}
+		if err := decoder.Decode(&remoteRow); err != nil {
+			return err
+		}
+		mapping.rowCount++
+
+		if onRow != nil {
+			row := make(map[string]string, len(remoteRow))
+			for column, cell := range remoteRow {
+				row[column] = cast.ToString(cell)
+			}
+			onRow(row)
+		}
+	}
+
+	return expectDelim(decoder, ']')
+}
+
+// unmarshalMappingFields func sets the decoded fields of the mapping besides its rows
+func unmarshalMappingFields(fields map[string]json.RawMessage, mapping *Mapping) error {
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, mapping)
+}
+
+// expectDelim func reads the next token and checks it is the delimiter
+func expectDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("expected %s, got %v", expected, token)
+	}
+	return nil
 }
 
 func dataSourceMapping() *schema.Resource {
@@ -25,14 +184,18 @@ func dataSourceMapping() *schema.Resource {
 		ReadContext: dataSourceReadMapping,
 		Schema: map[string]*schema.Schema{
 			"id": {
-				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "ID of the mapping",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "ID of the mapping, one of `id` or `name` must be specified",
+				ExactlyOneOf: []string{"id", "name"},
 			},
 			"name": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Name of the mapping",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Name of the mapping, one of `id` or `name` must be specified",
+				ExactlyOneOf: []string{"id", "name"},
 			},
 			"description": {
 				Type:        schema.TypeString,
@@ -44,6 +207,16 @@ func dataSourceMapping() *schema.Resource {
 				Computed:    true,
 				Description: "Name of the mapping file",
 			},
+			"priority": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Priority of the mapping",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Type of the mapping, either `csv` or `topology`",
+			},
 			"matchers": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -56,6 +229,11 @@ func dataSourceMapping() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "List of attributes",
 			},
+			"row_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of rows of the mapping",
+			},
 			"created_at": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -74,34 +252,54 @@ func dataSourceReadMapping(ctx context.Context, d *schema.ResourceData, m interf
 	client := m.(*Client)
 
 	id := d.Get("id").(int)
+	name := d.Get("name").(string)
 
-	req, err := http.NewRequest("GET", client.HostURL+"/mapping/", nil)
-
-	body, err := client.doReq(req)
+	mappings, err := getMappings(ctx, client, nil)
 	if err != nil {
-		return diag.Errorf("cannot send request: %s", err)
+		return diag.FromErr(err)
 	}
 
-	var response []Mapping
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return diag.Errorf("cannot unmarshal response: %s", err)
-	}
-
-	for _, mapping := range response {
-		if mapping.ID == id {
-			d.SetId(strconv.Itoa(id))
-			d.Set("id", strconv.Itoa(id))
-			d.Set("name", mapping.Name)
-			d.Set("description", mapping.Description)
-			d.Set("file_name", mapping.FileName)
-			d.Set("matchers", mapping.Matchers)
-			d.Set("attributes", mapping.Attributes)
-			d.Set("created_at", mapping.CreatedAt)
-			d.Set("created_by", mapping.CreatedBy)
-			break
+	var found []Mapping
+	for _, mapping := range mappings {
+		if (id != 0 && mapping.ID == id) || (id == 0 && mapping.Name == name) {
+			found = append(found, mapping)
+		}
+	}
+
+	switch {
+	case len(found) == 0 && id != 0:
+		return diag.Errorf("mapping with id %d not found", id)
+	case len(found) == 0:
+		return diag.Errorf("mapping with name %q not found", name)
+	case len(found) > 1:
+		// names are not unique in keep
+		return diag.Errorf("found %d mappings with name %q, use id to select one of them", len(found), name)
+	}
+
+	mapping := found[0]
+	d.SetId(strconv.Itoa(mapping.ID))
+	for key, value := range mappingToMap(mapping) {
+		if err := d.Set(key, value); err != nil {
+			return diag.Errorf("cannot set %s: %s", key, err)
 		}
 	}
 
 	return nil
 }
+
+// mappingToMap func converts the mapping to the attributes of the mapping data sources
+func mappingToMap(mapping Mapping) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          mapping.ID,
+		"name":        mapping.Name,
+		"description": mapping.Description,
+		"file_name":   mapping.FileName,
+		"priority":    mapping.Priority,
+		"type":        mapping.Type,
+		"matchers":    mapping.Matchers,
+		"attributes":  mapping.Attributes,
+		"row_count":   mapping.RowCount(),
+		"created_at":  mapping.CreatedAt,
+		"created_by":  mapping.CreatedBy,
+	}
+}
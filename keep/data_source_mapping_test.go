@@ -0,0 +1,85 @@
+package keep
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeMappings(t *testing.T) {
+	// keep sends the fields of the mappings in any order, the id may come after the rows
+	response := `[
+		{"name": "service_owners", "matchers": ["service"], "rows": [{"service": "checkout", "owner": "payments"}, {"service": "search", "replicas": 3, "cost": 1.50}], "id": 7, "last_updated_at": "2024-01-01T00:00:00"},
+		{"id": 8, "name": "topology", "type": "topology", "rows": null},
+		{"id": 9, "name": "empty", "rows": []}
+	]`
+
+	handled := make(map[int]Mapping)
+	rows := make(map[int][]map[string]string)
+	mappings, err := decodeMappings(json.NewDecoder(strings.NewReader(response)), func(index int, mapping Mapping) func(row map[string]string) {
+		handled[index] = mapping
+		return func(row map[string]string) {
+			rows[index] = append(rows[index], row)
+		}
+	})
+	if err != nil {
+		t.Fatalf("cannot decode mappings: %s", err)
+	}
+
+	if len(mappings) != 3 {
+		t.Fatalf("expected 3 mappings, got %d", len(mappings))
+	}
+	for i, expected := range []struct {
+		id       int
+		name     string
+		rowCount int
+		hasRows  bool
+	}{
+		{id: 7, name: "service_owners", rowCount: 2, hasRows: true},
+		{id: 8, name: "topology", rowCount: 0, hasRows: false},
+		{id: 9, name: "empty", rowCount: 0, hasRows: true},
+	} {
+		mapping := mappings[i]
+		if mapping.ID != expected.id || mapping.Name != expected.name || mapping.RowCount() != expected.rowCount || mapping.hasRows != expected.hasRows {
+			t.Errorf("expected mapping %d to be %+v, got %+v", i, expected, mapping)
+		}
+	}
+	if mappings[0].UpdatedAt != "2024-01-01T00:00:00" {
+		t.Errorf("expected the fields after the rows to be decoded, got %+v", mappings[0])
+	}
+
+	// the handler only knows the fields sent before the rows
+	if first := handled[0]; first.ID != 0 || first.Name != "service_owners" || !reflect.DeepEqual(first.Matchers, []string{"service"}) {
+		t.Errorf("expected the handler to get the fields before the rows, got %+v", first)
+	}
+	if second := handled[1]; second.ID != 8 || second.Type != "topology" {
+		t.Errorf("expected the handler to get the fields before the rows, got %+v", second)
+	}
+
+	expected := []map[string]string{
+		{"service": "checkout", "owner": "payments"},
+		// numbers are kept as keep sends them
+		{"service": "search", "replicas": "3", "cost": "1.50"},
+	}
+	if !reflect.DeepEqual(rows[0], expected) {
+		t.Errorf("expected rows %v, got %v", expected, rows[0])
+	}
+	if len(rows[1]) != 0 || len(rows[2]) != 0 {
+		t.Errorf("expected no rows for the other mappings, got %v and %v", rows[1], rows[2])
+	}
+}
+
+func TestDecodeMappingsRejectsInvalidResponses(t *testing.T) {
+	for name, response := range map[string]string{
+		"not a list":      `{"id": 7}`,
+		"rows not a list": `[{"id": 7, "rows": {"service": "checkout"}}]`,
+		"truncated":       `[{"id": 7, "rows": [{"service": "checkout"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeMappings(json.NewDecoder(strings.NewReader(response)), nil); err == nil {
+				t.Errorf("expected an error for %s", response)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package keep
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceMappings() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceReadMappings,
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Filter mappings by a regex matching the name",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsValidRegExp),
+			},
+			"matcher": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter mappings having the matcher",
+			},
+			"created_by": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter mappings by creator",
+			},
+			"mappings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of mappings matching the filters",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "ID of the mapping",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the mapping",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Description of the mapping",
+						},
+						"file_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the mapping file",
+						},
+						"priority": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Priority of the mapping",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the mapping, either `csv` or `topology`",
+						},
+						"matchers": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "List of matchers",
+						},
+						"attributes": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "List of attributes",
+						},
+						"row_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of rows of the mapping",
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Creation time of the mapping",
+						},
+						"created_by": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Creator of the mapping",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceReadMappings(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	matcher := d.Get("matcher").(string)
+	createdBy := d.Get("created_by").(string)
+
+	var nameRegex *regexp.Regexp
+	if v := d.Get("name_regex").(string); v != "" {
+		nameRegex = regexp.MustCompile(v)
+	}
+
+	response, err := getMappings(ctx, client, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	mappings := make([]map[string]interface{}, 0, len(response))
+	for _, mapping := range response {
+		if nameRegex != nil && !nameRegex.MatchString(mapping.Name) {
+			continue
+		}
+		if matcher != "" && !slices.Contains(mapping.Matchers, matcher) {
+			continue
+		}
+		if createdBy != "" && mapping.CreatedBy != createdBy {
+			continue
+		}
+
+		mappings = append(mappings, mappingToMap(mapping))
+	}
+
+	if err := d.Set("mappings", mappings); err != nil {
+		return diag.Errorf("cannot set mappings: %s", err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cast"
+)
+
+type Preset struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name"`
+	Options          []PresetOption `json:"options"`
+	CreatedBy        string         `json:"created_by"`
+	IsPrivate        bool           `json:"is_private"`
+	IsNoisy          bool           `json:"is_noisy"`
+	ShouldDoNoiseNow bool           `json:"should_do_noise_now"`
+	AlertsCount      int            `json:"alerts_count"`
+	Static           bool           `json:"static"`
+	Tags             []PresetTag    `json:"tags"`
+}
+
+type PresetOption struct {
+	Label string      `json:"label"`
+	Value interface{} `json:"value"`
+}
+
+type PresetTag struct {
+	ID   *int   `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// CELQuery returns the CEL query alerts of the preset match
+func (p Preset) CELQuery() string {
+	for _, option := range p.Options {
+		if strings.EqualFold(option.Label, "CEL") {
+			return cast.ToString(option.Value)
+		}
+	}
+	return ""
+}
+
+// TagNames returns the sorted names of the tags of the preset
+func (p Preset) TagNames() []string {
+	names := make([]string, len(p.Tags))
+	for i, tag := range p.Tags {
+		names[i] = tag.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getPresets func fetches the presets from keep
+func getPresets(client *Client) ([]Preset, error) {
+	// create new request
+	req, err := http.NewRequest("GET", client.HostURL+"/preset", nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	body, err := client.doReq(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response []Preset
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	return response, nil
+}
+
+func dataSourcePreset() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceReadPreset,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "ID of the preset, one of `id` or `name` must be specified",
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Name of the preset, one of `id` or `name` must be specified",
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"cel_query": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "CEL query alerts of the preset match",
+			},
+			"is_private": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the preset is only visible to its creator",
+			},
+			"is_noisy": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the preset makes noise when it has firing alerts",
+			},
+			"should_do_noise_now": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the preset currently makes noise",
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Tags of the preset",
+			},
+			"alerts_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of alerts matching the preset",
+			},
+			"created_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creator of the preset",
+			},
+		},
+	}
+}
+
+func dataSourceReadPreset(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	id := d.Get("id").(string)
+	name := d.Get("name").(string)
+
+	presets, err := getPresets(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var found []Preset
+	for _, preset := range presets {
+		if (id != "" && preset.ID == id) || (id == "" && preset.Name == name) {
+			found = append(found, preset)
+		}
+	}
+
+	switch {
+	case len(found) == 0 && id != "":
+		return diag.Errorf("preset with id %s not found", id)
+	case len(found) == 0:
+		return diag.Errorf("preset with name %q not found", name)
+	case len(found) > 1:
+		// one of the presets with the name is not picked arbitrarily
+		return diag.Errorf("found %d presets with name %q, use id to select one of them", len(found), name)
+	}
+
+	preset := found[0]
+	d.SetId(preset.ID)
+	d.Set("name", preset.Name)
+	d.Set("cel_query", preset.CELQuery())
+	d.Set("is_private", preset.IsPrivate)
+	d.Set("is_noisy", preset.IsNoisy)
+	d.Set("should_do_noise_now", preset.ShouldDoNoiseNow)
+	d.Set("tags", preset.TagNames())
+	d.Set("alerts_count", preset.AlertsCount)
+	d.Set("created_by", preset.CreatedBy)
+
+	return nil
+}
@@ -0,0 +1,516 @@
+package keep
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+// mappingFileFormats are the supported formats of mapping files
+var mappingFileFormats = []string{"csv", "tsv", "json", "yaml"}
+
+// maxMappingErrors is the number of problems reported when validating a mapping before giving up
+const maxMappingErrors = 20
+
+// byteOrderMark is written by some spreadsheet editors at the beginning of exported files
+const byteOrderMark = "\ufeff"
+
+// mappingReader reads the rows of a mapping file one by one, every format is normalized into rows of strings
+type mappingReader interface {
+	// Headers returns the column names of the mapping
+	Headers() []string
+	// Next returns the next row and its position in the file for error messages, io.EOF when there are no more rows
+	Next() (map[string]string, string, error)
+	// Close closes the underlying file
+	Close() error
+}
+
+// mappingFileFormat func returns the format of the mapping file, either the given one or the one detected from the file extension
+func mappingFileFormat(path string, format string) string {
+	if format != "" {
+		return format
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsv":
+		return "tsv"
+	case ".json":
+		return "json"
+	case ".yml", ".yaml":
+		return "yaml"
+	default:
+		return "csv"
+	}
+}
+
+// openMappingFile func opens the mapping file and returns a reader for its format,
+// the delimiter overrides the default one of csv and tsv files
+func openMappingFile(path string, format string, delimiter string) (mappingReader, error) {
+	// read file from path it should be a file path and a mapping file
+	fInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("mapping file not found: %s", path)
+	} else if fInfo.IsDir() {
+		return nil, fmt.Errorf("mapping file is a directory: %s", path)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %s", path)
+	}
+
+	content := skipByteOrderMark(file)
+
+	var reader mappingReader
+	switch mappingFileFormat(path, format) {
+	case "csv":
+		reader, err = newDelimitedMappingReader(file, content, delimiterOrDefault(delimiter, ','))
+	case "tsv":
+		reader, err = newDelimitedMappingReader(file, content, delimiterOrDefault(delimiter, '\t'))
+	case "json":
+		reader, err = newJSONMappingReader(file, content)
+	case "yaml":
+		reader, err = newYAMLMappingReader(file, content)
+	default:
+		err = fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("cannot read mapping file %s: %s", path, err)
+	}
+
+	return reader, nil
+}
+
+// validateMapping func reads every row of the mapping and returns all the problems found with their positions:
+// empty or duplicated header names, ragged rows, matchers that are not columns and rows with the same matcher key.
+// Every valid row is passed to onRow when it is not nil.
+func validateMapping(reader mappingReader, matchers []string, onRow func(map[string]string)) error {
+	var errs []error
+
+	headers := reader.Headers()
+	columns := make(map[string]int, len(headers))
+	for i, header := range headers {
+		if strings.TrimSpace(header) == "" {
+			errs = append(errs, fmt.Errorf("header: column %d has an empty name", i+1))
+			continue
+		}
+		if first, ok := columns[header]; ok {
+			errs = append(errs, fmt.Errorf("header: column %q is duplicated, see columns %d and %d", header, first+1, i+1))
+			continue
+		}
+		columns[header] = i
+	}
+
+	for _, matcher := range matchers {
+		if _, ok := columns[matcher]; !ok {
+			errs = append(errs, fmt.Errorf("matcher %q is not a column of the mapping, columns are: %s", matcher, strings.Join(headers, ", ")))
+		}
+	}
+
+	keys := make(map[string]string)
+	for len(errs) < maxMappingErrors {
+		row, pos, err := reader.Next()
+		if err == io.EOF {
+			return errors.Join(errs...)
+		}
+		if err != nil {
+			errs = append(errs, positionedError(pos, err))
+			var parseErr *csv.ParseError
+			if pos == "" && !errors.As(err, &parseErr) {
+				// the file cannot be read any further
+				return errors.Join(errs...)
+			}
+			continue
+		}
+
+		key := matcherKey(row, matchers)
+		if first, ok := keys[key]; ok {
+			errs = append(errs, fmt.Errorf("%s: matcher key %s is already used at %s", pos, key, first))
+			continue
+		}
+		keys[key] = pos
+
+		if onRow != nil {
+			onRow(row)
+		}
+	}
+
+	errs = append(errs, fmt.Errorf("too many errors, only the first %d are reported", maxMappingErrors))
+	return errors.Join(errs...)
+}
+
+// mappingDigest summarizes the rows of a mapping to detect changes made to them in keep
+type mappingDigest struct {
+	matchers  []string
+	rowHashes [][]byte
+	// fingerprints maps the matcher key of every row to a short hash of the row, it is nil unless rows are tracked
+	fingerprints map[string]string
+}
+
+// newMappingDigest func returns an empty digest, the fingerprints of the rows are only kept when trackRows is set
+// since they take an entry per row
+func newMappingDigest(matchers []string, trackRows bool) *mappingDigest {
+	digest := &mappingDigest{matchers: matchers}
+	if trackRows {
+		digest.fingerprints = make(map[string]string)
+	}
+	return digest
+}
+
+// add func adds the row to the digest
+func (g *mappingDigest) add(row map[string]string) {
+	// maps are marshaled with sorted keys, so equal rows have equal hashes
+	content, _ := json.Marshal(row)
+	rowHash := sha256.Sum256(content)
+
+	g.rowHashes = append(g.rowHashes, rowHash[:])
+	if g.fingerprints != nil {
+		g.fingerprints[matcherKey(row, g.matchers)] = hex.EncodeToString(rowHash[:4])
+	}
+}
+
+// hash func returns the hash of all the rows regardless of their order
+func (g *mappingDigest) hash() string {
+	sort.Slice(g.rowHashes, func(i, j int) bool { return bytes.Compare(g.rowHashes[i], g.rowHashes[j]) < 0 })
+
+	hash := sha256.New()
+	for _, rowHash := range g.rowHashes {
+		hash.Write(rowHash)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// matcherKey func returns the values of the matcher columns of the row, identifying the row within the mapping
+func matcherKey(row map[string]string, matchers []string) string {
+	values := make([]string, len(matchers))
+	for i, matcher := range matchers {
+		values[i] = fmt.Sprintf("%s=%q", matcher, row[matcher])
+	}
+	return strings.Join(values, ", ")
+}
+
+// positionedError func prefixes the error with its position in the mapping when known
+func positionedError(pos string, err error) error {
+	if pos == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %s", pos, err)
+}
+
+// skipByteOrderMark func returns the content of the file without the leading byte order mark
+func skipByteOrderMark(file *os.File) io.Reader {
+	content := bufio.NewReader(file)
+	if prefix, err := content.Peek(len(byteOrderMark)); err == nil && string(prefix) == byteOrderMark {
+		content.Discard(len(byteOrderMark))
+	}
+	return content
+}
+
+// delimiterOrDefault func returns the configured delimiter, or the default one of the format when not configured
+func delimiterOrDefault(delimiter string, defaultDelimiter rune) rune {
+	if delimiter == "" {
+		return defaultDelimiter
+	}
+	return []rune(delimiter)[0]
+}
+
+// delimitedMappingReader reads csv and tsv mapping files, the first record is the header
+type delimitedMappingReader struct {
+	file    *os.File
+	reader  *csv.Reader
+	headers []string
+	// first is the row read ahead to reject files without rows, with its position and error
+	first    []string
+	firstPos string
+	firstErr error
+	read     bool
+}
+
+func newDelimitedMappingReader(file *os.File, content io.Reader, delimiter rune) (*delimitedMappingReader, error) {
+	reader := csv.NewReader(content)
+	reader.Comma = delimiter
+	// the number of fields is checked by Next to report ragged rows clearly
+	reader.FieldsPerRecord = -1
+
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := &delimitedMappingReader{file: file, reader: reader, headers: headers}
+	r.first, r.firstPos, r.firstErr = r.readRecord()
+	if r.firstErr == io.EOF {
+		return nil, fmt.Errorf("mapping has no rows")
+	}
+
+	return r, nil
+}
+
+// readRecord func reads the next record with its position in the file
+func (r *delimitedMappingReader) readRecord() ([]string, string, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return nil, "", err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	return record, fmt.Sprintf("line %d", line), nil
+}
+
+func (r *delimitedMappingReader) Headers() []string {
+	return r.headers
+}
+
+func (r *delimitedMappingReader) Next() (map[string]string, string, error) {
+	record, pos, err := r.first, r.firstPos, r.firstErr
+	if r.read {
+		record, pos, err = r.readRecord()
+	}
+	r.read = true
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(record) != len(r.headers) {
+		return nil, pos, fmt.Errorf("row has %d columns, header has %d", len(record), len(r.headers))
+	}
+
+	row := make(map[string]string, len(record))
+	for i, cell := range record {
+		row[r.headers[i]] = cell
+	}
+
+	return row, pos, nil
+}
+
+func (r *delimitedMappingReader) Close() error {
+	return r.file.Close()
+}
+
+// objectMappingReader reads mapping files made of a list of objects and inline rows, the keys of the first object are the header
+type objectMappingReader struct {
+	// file is nil for inline rows
+	file    *os.File
+	objects []map[string]interface{}
+	// positions of the objects in the file
+	positions []string
+	headers   []string
+	next      int
+}
+
+func newYAMLMappingReader(file *os.File, content io.Reader) (*objectMappingReader, error) {
+	var document yaml.Node
+	if err := yaml.NewDecoder(content).Decode(&document); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if len(document.Content) == 0 {
+		return newObjectMappingReader(file, nil, nil)
+	}
+	list := document.Content[0]
+	if list.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("expected a YAML list of objects, line %d", list.Line)
+	}
+
+	objects := make([]map[string]interface{}, len(list.Content))
+	positions := make([]string, len(list.Content))
+	for i, item := range list.Content {
+		positions[i] = fmt.Sprintf("line %d", item.Line)
+		object, err := yamlObject(item)
+		if err != nil {
+			return nil, fmt.Errorf("expected a YAML list of objects, %s: %s", positions[i], err)
+		}
+		objects[i] = object
+	}
+
+	return newObjectMappingReader(file, objects, positions)
+}
+
+// yamlObject func converts a YAML object of a mapping file to an object keeping the scalar values as they are written,
+// decoding them would turn values like 2024-01-01 into timestamps and 1.50 into 1.5
+func yamlObject(node *yaml.Node) (map[string]interface{}, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("item is not an object")
+	}
+
+	object := make(map[string]interface{}, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if _, ok := object[key.Value]; ok {
+			return nil, fmt.Errorf("key %q is duplicated, line %d", key.Value, key.Line)
+		}
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+
+		switch {
+		case value.Kind == yaml.MappingNode:
+			object[key.Value] = map[string]interface{}{}
+		case value.Kind == yaml.SequenceNode:
+			object[key.Value] = []interface{}{}
+		case value.ShortTag() == "!!null":
+			object[key.Value] = nil
+		default:
+			object[key.Value] = value.Value
+		}
+	}
+
+	return object, nil
+}
+
+// newInlineMappingReader func returns a reader over the inline rows of the mapping
+func newInlineMappingReader(rows []interface{}) (*objectMappingReader, error) {
+	objects := make([]map[string]interface{}, len(rows))
+	positions := make([]string, len(rows))
+	for i, row := range rows {
+		objects[i], _ = row.(map[string]interface{})
+		positions[i] = fmt.Sprintf("row %d", i+1)
+	}
+
+	return newObjectMappingReader(nil, objects, positions)
+}
+
+func newObjectMappingReader(file *os.File, objects []map[string]interface{}, positions []string) (*objectMappingReader, error) {
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("mapping has no rows")
+	}
+
+	return &objectMappingReader{file: file, objects: objects, positions: positions, headers: objectHeaders(objects[0])}, nil
+}
+
+func (r *objectMappingReader) Headers() []string {
+	return r.headers
+}
+
+func (r *objectMappingReader) Next() (map[string]string, string, error) {
+	if r.next >= len(r.objects) {
+		return nil, "", io.EOF
+	}
+	object, pos := r.objects[r.next], r.positions[r.next]
+	r.next++
+
+	row, err := objectToRow(object, r.headers)
+	return row, pos, err
+}
+
+func (r *objectMappingReader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
+
+// jsonMappingReader reads JSON mapping files one object at a time, so large files are never held in memory as a whole
+type jsonMappingReader struct {
+	file    *os.File
+	decoder *json.Decoder
+	headers []string
+	// first is the object read ahead for the headers
+	first map[string]interface{}
+	item  int
+}
+
+func newJSONMappingReader(file *os.File, content io.Reader) (*jsonMappingReader, error) {
+	decoder := json.NewDecoder(content)
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("expected a JSON array of objects: %s", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("expected a JSON array of objects")
+	}
+	if !decoder.More() {
+		return nil, fmt.Errorf("mapping has no rows")
+	}
+
+	var first map[string]interface{}
+	if err := decoder.Decode(&first); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of objects, item 1: %s", err)
+	}
+
+	return &jsonMappingReader{file: file, decoder: decoder, headers: objectHeaders(first), first: first}, nil
+}
+
+func (r *jsonMappingReader) Headers() []string {
+	return r.headers
+}
+
+func (r *jsonMappingReader) Next() (map[string]string, string, error) {
+	object := r.first
+	r.first = nil
+	if object == nil {
+		if !r.decoder.More() {
+			return nil, "", io.EOF
+		}
+		// the decoder can't recover from syntax errors, they are returned without a position to stop reading
+		if err := r.decoder.Decode(&object); err != nil {
+			return nil, "", fmt.Errorf("expected a JSON array of objects, item %d: %s", r.item+1, err)
+		}
+	}
+	r.item++
+
+	row, err := objectToRow(object, r.headers)
+	return row, fmt.Sprintf("item %d", r.item), err
+}
+
+func (r *jsonMappingReader) Close() error {
+	return r.file.Close()
+}
+
+// objectHeaders func returns the sorted keys of the object, they are the header of mapping files made of objects
+func objectHeaders(object map[string]interface{}) []string {
+	headers := make([]string, 0, len(object))
+	for column := range object {
+		headers = append(headers, column)
+	}
+	sort.Strings(headers)
+	return headers
+}
+
+// objectToRow func converts an object of a mapping file to a row, every object must have exactly the header columns with scalar values
+func objectToRow(object map[string]interface{}, headers []string) (map[string]string, error) {
+	if len(object) != len(headers) {
+		return nil, fmt.Errorf("row has %d columns, header has %d", len(object), len(headers))
+	}
+
+	row := make(map[string]string, len(object))
+	for _, column := range headers {
+		value, ok := object[column]
+		if !ok {
+			return nil, fmt.Errorf("row is missing column %q", column)
+		}
+
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("column %q must be a scalar value", column)
+		case nil:
+			row[column] = ""
+		default:
+			row[column] = cast.ToString(value)
+		}
+	}
+
+	return row, nil
+}
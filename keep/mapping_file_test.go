@@ -0,0 +1,206 @@
+package keep
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeMappingFile func writes the content to a mapping file with the name in a temporary directory and returns its path
+func writeMappingFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("cannot write mapping file: %s", err)
+	}
+	return path
+}
+
+// readMappingRows func reads every row of the mapping file, failing the test when the mapping is not valid
+func readMappingRows(t *testing.T, path string, format string, delimiter string, matchers []string) []map[string]string {
+	t.Helper()
+
+	reader, err := openMappingFile(path, format, delimiter)
+	if err != nil {
+		t.Fatalf("cannot open mapping file: %s", err)
+	}
+	defer reader.Close()
+
+	var rows []map[string]string
+	if err := validateMapping(reader, matchers, func(row map[string]string) { rows = append(rows, row) }); err != nil {
+		t.Fatalf("mapping is not valid: %s", err)
+	}
+	return rows
+}
+
+func TestYAMLMappingKeepsScalarValues(t *testing.T) {
+	path := writeMappingFile(t, "mapping.yaml", `- service: checkout
+  since: 2024-01-01
+  cost: 1.50
+  replicas: 010
+  enabled: yes
+  owner: ~
+- service: &search search
+  since: 2024-02-01T10:00:00Z
+  cost: 2.0
+  replicas: 3
+  enabled: false
+  owner: *search
+`)
+
+	rows := readMappingRows(t, path, "", "", []string{"service"})
+	expected := []map[string]string{
+		{"service": "checkout", "since": "2024-01-01", "cost": "1.50", "replicas": "010", "enabled": "yes", "owner": ""},
+		{"service": "search", "since": "2024-02-01T10:00:00Z", "cost": "2.0", "replicas": "3", "enabled": "false", "owner": "search"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected rows %v, got %v", expected, rows)
+	}
+}
+
+func TestYAMLMappingRejectsInvalidObjects(t *testing.T) {
+	for name, content := range map[string]string{
+		"not a list":     "service: checkout\n",
+		"not an object":  "- checkout\n",
+		"duplicated key": "- service: checkout\n  service: search\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := writeMappingFile(t, "mapping.yaml", content)
+			if reader, err := openMappingFile(path, "", ""); err == nil {
+				reader.Close()
+				t.Errorf("expected an error for %q", content)
+			}
+		})
+	}
+
+	path := writeMappingFile(t, "mapping.yaml", "- service: checkout\n  owner: [payments]\n")
+	reader, err := openMappingFile(path, "", "")
+	if err != nil {
+		t.Fatalf("cannot open mapping file: %s", err)
+	}
+	defer reader.Close()
+	if err := validateMapping(reader, []string{"service"}, nil); err == nil {
+		t.Errorf("expected an error for a list value")
+	}
+}
+
+func TestValidateMapping(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		file      string
+		content   string
+		delimiter string
+		matchers  []string
+		rows      []map[string]string
+		errors    []string
+	}{
+		{
+			name:     "byte order mark",
+			file:     "mapping.csv",
+			content:  "\ufeffservice,owner\ncheckout,payments\n",
+			matchers: []string{"service"},
+			rows:     []map[string]string{{"service": "checkout", "owner": "payments"}},
+		},
+		{
+			name:     "tsv",
+			file:     "mapping.tsv",
+			content:  "service\towner\ncheckout\tpayments, billing\n",
+			matchers: []string{"service"},
+			rows:     []map[string]string{{"service": "checkout", "owner": "payments, billing"}},
+		},
+		{
+			name:      "delimiter override",
+			file:      "mapping.csv",
+			content:   "service;owner\ncheckout;payments, billing\n",
+			delimiter: ";",
+			matchers:  []string{"service"},
+			rows:      []map[string]string{{"service": "checkout", "owner": "payments, billing"}},
+		},
+		{
+			name:     "duplicated and empty headers",
+			file:     "mapping.csv",
+			content:  "service,owner,owner,\ncheckout,payments,billing,x\n",
+			matchers: []string{"service"},
+			errors: []string{
+				`header: column "owner" is duplicated, see columns 2 and 3`,
+				"header: column 4 has an empty name",
+			},
+		},
+		{
+			name:     "matcher is not a column",
+			file:     "mapping.csv",
+			content:  "service,owner\ncheckout,payments\n",
+			matchers: []string{"team"},
+			errors:   []string{`matcher "team" is not a column of the mapping, columns are: service, owner`},
+		},
+		{
+			name:     "ragged rows",
+			file:     "mapping.csv",
+			content:  "service,owner\ncheckout,payments,billing\nsearch\ncart,orders\n",
+			matchers: []string{"service"},
+			errors: []string{
+				"line 2: row has 3 columns, header has 2",
+				"line 3: row has 1 columns, header has 2",
+			},
+		},
+		{
+			name:     "duplicated matcher keys",
+			file:     "mapping.csv",
+			content:  "service,env,owner\ncheckout,prod,payments\ncheckout,dev,payments\ncheckout,prod,billing\n",
+			matchers: []string{"service", "env"},
+			errors:   []string{`line 4: matcher key service="checkout", env="prod" is already used at line 2`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reader, err := openMappingFile(writeMappingFile(t, tc.file, tc.content), "", tc.delimiter)
+			if err != nil {
+				t.Fatalf("cannot open mapping file: %s", err)
+			}
+			defer reader.Close()
+
+			var rows []map[string]string
+			err = validateMapping(reader, tc.matchers, func(row map[string]string) { rows = append(rows, row) })
+			if len(tc.errors) == 0 {
+				if err != nil {
+					t.Fatalf("expected a valid mapping, got %s", err)
+				}
+				if !reflect.DeepEqual(rows, tc.rows) {
+					t.Errorf("expected rows %v, got %v", tc.rows, rows)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected errors %v, got none", tc.errors)
+			}
+			for _, expected := range tc.errors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error %q, got %s", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMappingFileWithoutRows(t *testing.T) {
+	for name, content := range map[string]string{
+		"mapping.csv":  "service,owner\n",
+		"mapping.tsv":  "service\towner\n",
+		"mapping.json": "[]",
+		"mapping.yaml": "[]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			reader, err := openMappingFile(writeMappingFile(t, name, content), "", "")
+			if err == nil {
+				reader.Close()
+				t.Fatalf("expected an error for a mapping without rows")
+			}
+			if !strings.Contains(err.Error(), "mapping has no rows") {
+				t.Errorf("expected mapping has no rows, got %s", err)
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+//go:generate go run ../internal/providergen -catalog ../internal/providergen/providers.json -output resource_provider_types_gen.go
+
 // Provider for Keep
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"backend_url": {
 				Type:        schema.TypeString,
@@ -27,17 +29,48 @@ func Provider() *schema.Provider {
 				Default:     "30s",
 				DefaultFunc: schema.EnvDefaultFunc("KEEP_TIMEOUT", "30s"),
 			},
+			"max_request_size": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Maximum size of a request body in bytes, requests exceeding it fail with an error instead of being sent. Default is 0, no limit.",
+				DefaultFunc: schema.EnvDefaultFunc("KEEP_MAX_REQUEST_SIZE", 0),
+			},
+			"compress_requests": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Compress request bodies with gzip. Keep doesn't decompress requests itself, enable it only when keep is behind a proxy that does. Default is false.",
+				DefaultFunc: schema.EnvDefaultFunc("KEEP_COMPRESS_REQUESTS", false),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"keep_provider":   resourceProvider(),
-			"keep_workflow":   resourceWorkflow(),
-			"keep_mapping":    resourceMapping(),
-			"keep_extraction": resourceExtraction(),
+			"keep_provider":           resourceProvider(),
+			"keep_workflow":           resourceWorkflow(),
+			"keep_mapping":            resourceMapping(),
+			"keep_extraction":         resourceExtraction(),
+			"keep_extraction_set":     resourceExtractionSet(),
+			"keep_preset":             resourcePreset(),
+			"keep_maintenance_window": resourceMaintenanceWindow(),
+			"keep_correlation_rule":   resourceCorrelationRule(),
+			"keep_deduplication_rule": resourceDeduplicationRule(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"keep_workflow": dataSourceWorkflows(),
-			"keep_mapping":  dataSourceMapping(),
+			"keep_workflow":            dataSourceWorkflows(),
+			"keep_mapping":             dataSourceMapping(),
+			"keep_mappings":            dataSourceMappings(),
+			"keep_extraction":          dataSourceExtraction(),
+			"keep_extractions":         dataSourceExtractions(),
+			"keep_preset":              dataSourcePreset(),
+			"keep_deduplication_rules": dataSourceDeduplicationRules(),
+			"keep_installed_providers": dataSourceInstalledProviders(),
+			"keep_extraction_test":     dataSourceExtractionDryRun(),
 		},
 		ConfigureContextFunc: ClientConfigurer,
 	}
+
+	// typed provider resources, e.g. keep_provider_prometheus
+	for providerType, typed := range typedProviders() {
+		provider.ResourcesMap["keep_provider_"+typed.ResourceName] = resourceTypedProvider(providerType, typed)
+	}
+
+	return provider
 }
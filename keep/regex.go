@@ -0,0 +1,170 @@
+package keep
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// regexConstruct is a part of a regex that python's re module, which keep evaluates regexes with,
+// and go's regexp package don't handle the same way
+type regexConstruct struct {
+	text string
+	hint string
+	// pythonOnly constructs are valid for keep but can't be compiled with go's regexp package,
+	// the others are go only and don't work in keep
+	pythonOnly bool
+}
+
+// scanPythonRegex func scans a regex written for python's re module, it returns the names of its named groups
+// in order and the constructs go's regexp package doesn't handle the same way
+func scanPythonRegex(pattern string) ([]string, []regexConstruct) {
+	var groups []string
+	var constructs []regexConstruct
+
+	inClass := false
+	classStart := 0
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			next := pattern[i]
+			switch {
+			case next >= '1' && next <= '9' && !inClass:
+				constructs = append(constructs, regexConstruct{text: pattern[i-1 : i+1], hint: "backreferences are not supported by go", pythonOnly: true})
+			case next == 'z':
+				constructs = append(constructs, regexConstruct{text: `\z`, hint: `use \Z to match the end of the string`})
+			case next == 'p' || next == 'P':
+				constructs = append(constructs, regexConstruct{text: pattern[i-1 : i+1], hint: "use an explicit class like [a-z] instead"})
+			case next == 'Q':
+				constructs = append(constructs, regexConstruct{text: `\Q...\E`, hint: "escape the special characters one by one instead"})
+			}
+		case inClass:
+			if c == ']' && i > classStart {
+				inClass = false
+			} else if c == '[' && i+1 < len(pattern) && pattern[i+1] == ':' {
+				constructs = append(constructs, regexConstruct{text: "[:class:]", hint: "use an explicit class like [a-zA-Z] instead"})
+			}
+		case c == '[':
+			inClass = true
+			// a ] right after the opening bracket is a literal
+			classStart = i + 1
+			if classStart < len(pattern) && pattern[classStart] == '^' {
+				classStart++
+			}
+		case c == '(' && strings.HasPrefix(pattern[i:], "(?"):
+			rest := pattern[i+2:]
+			switch {
+			case strings.HasPrefix(rest, "P<"):
+				if end := strings.IndexByte(rest, '>'); end > 2 {
+					groups = append(groups, rest[2:end])
+				}
+			case strings.HasPrefix(rest, "P="):
+				constructs = append(constructs, regexConstruct{text: "(?P=name)", hint: "backreferences are not supported by go", pythonOnly: true})
+			case strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, "!"):
+				constructs = append(constructs, regexConstruct{text: "(?" + rest[:1] + "...)", hint: "lookaheads are not supported by go", pythonOnly: true})
+			case strings.HasPrefix(rest, "<=") || strings.HasPrefix(rest, "<!"):
+				constructs = append(constructs, regexConstruct{text: "(?" + rest[:2] + "...)", hint: "lookbehinds are not supported by go", pythonOnly: true})
+			case strings.HasPrefix(rest, "<"):
+				constructs = append(constructs, regexConstruct{text: "(?<name>...)", hint: "use (?P<name>...) for named groups"})
+			case strings.HasPrefix(rest, ">"):
+				constructs = append(constructs, regexConstruct{text: "(?>...)", hint: "atomic groups are not supported by go", pythonOnly: true})
+			case strings.HasPrefix(rest, "#"):
+				constructs = append(constructs, regexConstruct{text: "(?#...)", hint: "comments are not supported by go", pythonOnly: true})
+			case strings.HasPrefix(rest, "("):
+				constructs = append(constructs, regexConstruct{text: "(?(group)yes|no)", hint: "conditionals are not supported by go", pythonOnly: true})
+			default:
+				constructs = append(constructs, scanRegexFlags(rest)...)
+			}
+		}
+	}
+
+	return groups, constructs
+}
+
+// scanRegexFlags func checks the inline flags of a group like (?i) or (?s:...)
+func scanRegexFlags(rest string) []regexConstruct {
+	var constructs []regexConstruct
+	for _, flag := range rest {
+		switch flag {
+		case 'U':
+			constructs = append(constructs, regexConstruct{text: "(?U)", hint: "python has no ungreedy flag, make the quantifiers lazy with ? instead"})
+		case 'a', 'L', 'u', 'x':
+			constructs = append(constructs, regexConstruct{text: fmt.Sprintf("(?%c)", flag), hint: "the flag is not supported by go", pythonOnly: true})
+		case ':', ')':
+			return constructs
+		}
+	}
+	return constructs
+}
+
+// validatePythonRegex func validates regexes keep evaluates with python's re module. Regexes must have at least one
+// named group, constructs that only work in go are errors and constructs that only work in python are warnings
+// since the regex can't be compiled at plan time then.
+func validatePythonRegex(v interface{}, path cty.Path) diag.Diagnostics {
+	pattern := v.(string)
+	groups, constructs := scanPythonRegex(pattern)
+
+	var diags diag.Diagnostics
+	compile := true
+	for _, construct := range constructs {
+		if construct.pythonOnly {
+			compile = false
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       "Regex can't be validated",
+				Detail:        fmt.Sprintf("%s is only checked by keep when the extraction runs, %s.", construct.text, construct.hint),
+				AttributePath: path,
+			})
+			continue
+		}
+
+		compile = false
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Regex is not supported by keep",
+			Detail:        fmt.Sprintf("%s doesn't work in python's re module which keep evaluates regexes with, %s.", construct.text, construct.hint),
+			AttributePath: path,
+		})
+	}
+
+	if compile {
+		// \Z of python is \z in go
+		if _, err := regexp.Compile(strings.ReplaceAll(pattern, `\Z`, `\z`)); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid regex",
+				Detail:        err.Error(),
+				AttributePath: path,
+			})
+		}
+	}
+
+	if len(groups) == 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Regex has no named groups",
+			Detail:        "Keep extracts the named groups of the regex as attributes of the alert, add at least one like (?P<name>...).",
+			AttributePath: path,
+		})
+	}
+
+	seen := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if seen[group] {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid regex",
+				Detail:        fmt.Sprintf("named group %q is defined more than once", group),
+				AttributePath: path,
+			})
+		}
+		seen[group] = true
+	}
+
+	return diags
+}
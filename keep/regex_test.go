@@ -0,0 +1,92 @@
+package keep
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestScanPythonRegex(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		pattern    string
+		groups     []string
+		constructs []string
+	}{
+		{name: "named groups", pattern: `(?P<host>[a-z0-9-]+):(?P<port>\d+)`, groups: []string{"host", "port"}},
+		{name: "unnamed and non capturing groups", pattern: `(\w+)(?:-(?P<env>\w+))?`, groups: []string{"env"}},
+		{name: "escaped parenthesis", pattern: `\(?P<host>\w+\)`},
+		{name: "escaped backslash before a group", pattern: `\\(?P<host>\w+)`, groups: []string{"host"}},
+		{name: "group in a character class", pattern: `[(?P<host>]+`},
+		{name: "bracket first in a class", pattern: `[]()?](?P<rest>.*)`, groups: []string{"rest"}},
+		{name: "bracket first in a negated class", pattern: `[^](](?P<rest>.*)`, groups: []string{"rest"}},
+		{name: "escaped bracket in a class", pattern: `[\]()](?P<rest>.*)`, groups: []string{"rest"}},
+		{name: "backreference", pattern: `(?P<word>\w+) \1`, groups: []string{"word"}, constructs: []string{`\1`}},
+		{name: "digit in a class is not a backreference", pattern: `(?P<digit>[\1-\9])`, groups: []string{"digit"}},
+		{name: "named backreference", pattern: `(?P<word>\w+) (?P=word)`, groups: []string{"word"}, constructs: []string{"(?P=name)"}},
+		{name: "lookarounds", pattern: `(?=a)(?!b)(?<=c)(?<!d)(?P<x>x)`, groups: []string{"x"}, constructs: []string{"(?=...)", "(?!...)", "(?<=...)", "(?<!...)"}},
+		{name: "go named group", pattern: `(?<host>\w+)`, constructs: []string{"(?<name>...)"}},
+		{name: "atomic group, comment and conditional", pattern: `(?>a)(?#note)(?(1)b|c)`, constructs: []string{"(?>...)", "(?#...)", "(?(group)yes|no)"}},
+		{name: "end of string", pattern: `(?P<x>\w+)\z`, groups: []string{"x"}, constructs: []string{`\z`}},
+		{name: "python end of string", pattern: `(?P<x>\w+)\Z`, groups: []string{"x"}},
+		{name: "unicode classes", pattern: `(?P<x>\pL+)\P{Greek}`, groups: []string{"x"}, constructs: []string{`\p`, `\P`}},
+		{name: "quoted text", pattern: `\Qa.b\E(?P<x>.*)`, groups: []string{"x"}, constructs: []string{`\Q...\E`}},
+		{name: "posix class", pattern: `(?P<x>[[:alpha:]]+)`, groups: []string{"x"}, constructs: []string{"[:class:]"}},
+		{name: "common flags", pattern: `(?is)(?P<x>.*)(?m:^$)`, groups: []string{"x"}},
+		{name: "go only flag", pattern: `(?U)(?P<x>.*)`, groups: []string{"x"}, constructs: []string{"(?U)"}},
+		{name: "python only flags", pattern: `(?ax)(?P<x>.*)(?u:.)`, groups: []string{"x"}, constructs: []string{"(?a)", "(?x)", "(?u)"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			groups, constructs := scanPythonRegex(tc.pattern)
+			if !reflect.DeepEqual(groups, tc.groups) {
+				t.Errorf("expected groups %v, got %v", tc.groups, groups)
+			}
+
+			var texts []string
+			for _, construct := range constructs {
+				texts = append(texts, construct.text)
+			}
+			if !reflect.DeepEqual(texts, tc.constructs) {
+				t.Errorf("expected constructs %v, got %v", tc.constructs, texts)
+			}
+		})
+	}
+}
+
+func TestValidatePythonRegex(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		pattern  string
+		errors   []string
+		warnings []string
+	}{
+		{name: "valid", pattern: `(?P<host>[a-z0-9-]+):(?P<port>\d+)\Z`},
+		{name: "no named groups", pattern: `([a-z]+)`, errors: []string{"Regex has no named groups"}},
+		{name: "duplicated named group", pattern: `(?P<x>a)|(?P<x>b)`, errors: []string{"Invalid regex"}},
+		{name: "invalid", pattern: `(?P<x>[a-z)`, errors: []string{"Invalid regex"}},
+		{name: "go only construct", pattern: `(?<x>a)`, errors: []string{"Regex is not supported by keep", "Regex has no named groups"}},
+		{name: "python only construct", pattern: `(?P<x>\w+)(?=:)`, warnings: []string{"Regex can't be validated"}},
+		// python only constructs keep the regex from being compiled, so its other errors are left to keep
+		{name: "python only construct in an invalid regex", pattern: `(?P<x>\w+)(?=:)[`, warnings: []string{"Regex can't be validated"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var errors, warnings []string
+			for _, d := range validatePythonRegex(tc.pattern, cty.GetAttrPath("regex")) {
+				if d.Severity == diag.Error {
+					errors = append(errors, d.Summary)
+				} else {
+					warnings = append(warnings, d.Summary)
+				}
+			}
+
+			if !reflect.DeepEqual(errors, tc.errors) {
+				t.Errorf("expected errors %v, got %v", tc.errors, errors)
+			}
+			if !reflect.DeepEqual(warnings, tc.warnings) {
+				t.Errorf("expected warnings %v, got %v", tc.warnings, warnings)
+			}
+		})
+	}
+}
@@ -6,49 +6,122 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cast"
 	"net/http"
 	"strings"
 )
 
+// providerAuthConfig abstracts how the type and the authentication config of a provider are kept in the resource data,
+// so keep_provider and the typed keep_provider_<type> resources share the same implementation
+type providerAuthConfig interface {
+	// providerType returns the type of the provider
+	providerType(d *schema.ResourceData) string
+	// authConfig returns the authentication config sent to keep
+	authConfig(d *schema.ResourceData) map[string]interface{}
+	// read sets the type and the authentication config of the installed provider
+	read(d *schema.ResourceData, provider InstalledProvider) error
+}
+
+// providerSecretsNote explains in the descriptions of keep_provider and the typed provider resources
+// how secrets are handled, since keep doesn't return them
+const providerSecretsNote = "Keep doesn't return secret configuration values such as passwords or API keys, they are kept in the state as configured " +
+	"and changes made to them outside of terraform are not detected. After an import, secret values are missing from the state, " +
+	"so the next apply updates the provider with the configured `auth_config`."
+
 func resourceProvider() *schema.Resource {
+	providerSchema := providerCommonSchema()
+	providerSchema["type"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "Type of the keep provider",
+	}
+	providerSchema["auth_config"] = &schema.Schema{
+		Type:        schema.TypeMap,
+		Required:    true,
+		Description: "Configuration of the keep provider authentication. Secret values can't be read back from keep.",
+	}
+
 	return &schema.Resource{
-		CreateContext: resourceCreateProvider,
-		ReadContext:   resourceReadProvider,
-		UpdateContext: resourceUpdateProvider,
-		DeleteContext: resourceDeleteProvider,
+		CreateContext: resourceCreateProvider(mapAuthConfig{}),
+		ReadContext:   resourceReadProvider(mapAuthConfig{}),
+		UpdateContext: resourceUpdateProvider(mapAuthConfig{}),
+		DeleteContext: resourceDeleteProvider(mapAuthConfig{}),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceImportProvider(mapAuthConfig{}),
+		},
+		Description: "Manages a provider installed in keep.\n\n" + providerSecretsNote,
+		Schema:      providerSchema,
+	}
+}
+
+// providerCommonSchema func returns the attributes shared by keep_provider and the typed provider resources
+func providerCommonSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Name of the keep provider",
+		},
+		"install_webhook": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Install webhook for the provider (default: false). Keep doesn't report whether the webhook is installed, so the value is kept in the state as configured and a webhook removed outside of terraform is not detected. After an import, the next apply installs the webhook when it is enabled",
+		},
+		"required_scopes": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Scopes that must be validated by keep, the apply fails if any of them is not valid",
+		},
+		"rollback_on_scope_failure": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Uninstall the provider if any of the required scopes is not valid after installation (default: false)",
+		},
+		"validated_scopes": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Scope validation results, `true` for valid scopes or the validation error otherwise",
 		},
-		Schema: map[string]*schema.Schema{
-			"type": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Type of the keep provider",
-			},
-			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Name of the keep provider",
-			},
-			"auth_config": {
-				Type:        schema.TypeMap,
-				Required:    true,
-				Description: "Configuration of the keep provider authentication",
-			},
-			"install_webhook": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "Install webhook for the provider (default: false)",
-			},
+		"pulling_enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Pull alerts from the provider periodically, disable it to rely on pushed alerts only (default: true). The pulling interval is set for all providers in keep's own configuration, it can't be set per provider",
 		},
 	}
 }
 
-func resourceCreateProvider(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
-	providerType := d.Get("type").(string)
-	providerName := d.Get("name").(string)
+// mapAuthConfig keeps the type and the authentication config of keep_provider as a string and a map of strings
+type mapAuthConfig struct{}
+
+func (mapAuthConfig) providerType(d *schema.ResourceData) string {
+	return d.Get("type").(string)
+}
+
+func (mapAuthConfig) authConfig(d *schema.ResourceData) map[string]interface{} {
+	return d.Get("auth_config").(map[string]interface{})
+}
+
+func (mapAuthConfig) read(d *schema.ResourceData, provider InstalledProvider) error {
+	if err := d.Set("type", provider.Type); err != nil {
+		return err
+	}
+	return d.Set("auth_config", readAuthConfig(provider, d.Get("auth_config").(map[string]interface{})))
+}
+
+func resourceCreateProvider(h providerAuthConfig) schema.CreateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return createProvider(d, m.(*Client), h)
+	}
+}
+
+func createProvider(d *schema.ResourceData, client *Client, h providerAuthConfig) diag.Diagnostics {
+	providerType := h.providerType(d)
 
 	// create new request
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/providers", client.HostURL), nil)
@@ -70,7 +143,6 @@ func resourceCreateProvider(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	found := false
-	authConfigs := d.Get("auth_config").(map[string]interface{})
 	availableProviders := providers["providers"].([]interface{})
 
 	for _, provider := range availableProviders {
@@ -87,15 +159,7 @@ func resourceCreateProvider(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	// Prepare the payload for the provider installation request
-	providerInstallPayload := map[string]interface{}{
-		"provider_id":   providerType,
-		"provider_name": providerName,
-	}
-
-	// Add the auth config to the payload
-	for key, value := range authConfigs {
-		providerInstallPayload[key] = value
-	}
+	providerInstallPayload := providerPayload(d, h)
 
 	// Marshal the payload
 	payload, err := json.Marshal(providerInstallPayload)
@@ -125,144 +189,286 @@ func resourceCreateProvider(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("couldn't create provider properly, response is nil")
 	}
 
-	if d.Get("install_webhook").(bool) {
-		// Create a new request
-		req, err = http.NewRequest("POST", fmt.Sprintf("%s/providers/install/webhook/%s/%s", client.HostURL, providerType, response["id"].(string)), nil)
-		if err != nil {
-			return diag.Errorf("cannot create request: %s", err)
+	// Check the validated scopes against the required ones
+	scopes := scopesToStrings(response["validatedScopes"])
+	if err := checkRequiredScopes(d.Get("required_scopes").([]interface{}), scopes); err != nil {
+		if d.Get("rollback_on_scope_failure").(bool) {
+			if rollbackErr := deleteProvider(client, providerType, response["id"].(string)); rollbackErr != nil {
+				return diag.Errorf("%s, rollback failed: %s", err, rollbackErr)
+			}
+			return diag.Errorf("%s, provider installation rolled back", err)
 		}
 
-		// Do the request
-		_, err := client.doReq(req)
-		if err != nil {
-			return diag.Errorf("cannot send request: %s", err)
+		d.SetId(response["id"].(string))
+		d.Set("validated_scopes", scopes)
+		return diag.FromErr(err)
+	}
+
+	if d.Get("install_webhook").(bool) {
+		if err := installWebhook(client, providerType, response["id"].(string)); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Set the ID
 	id := response["id"].(string)
 	d.SetId(id)
-	d.Set("id", id)
-	d.Set("type", providerType)
-	d.Set("name", providerName)
-	d.Set("auth_config", authConfigs)
-	d.Set("install_webhook", d.Get("install_webhook"))
+	d.Set("validated_scopes", scopes)
 
 	return nil
 }
 
-func resourceDeleteProvider(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+func resourceDeleteProvider(h providerAuthConfig) schema.DeleteContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client := m.(*Client)
 
-	id := d.Id()
-	providerType := d.Get("type").(string)
+		if err := deleteProvider(client, h.providerType(d), d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+
+		return nil
+	}
+}
 
+// deleteProvider func uninstalls the provider from keep
+func deleteProvider(client *Client, providerType string, id string) error {
 	// create new request
 	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/providers/%s/%s", client.HostURL, providerType, id), nil)
 	if err != nil {
-		return diag.Errorf("cannot create request: %s", err)
+		return fmt.Errorf("cannot create request: %s", err)
 	}
 
 	// send request
 	_, err = client.doReq(req)
 	if err != nil {
-		return diag.Errorf("cannot send request: %s", err)
+		return fmt.Errorf("cannot send request: %s", err)
 	}
 
 	return nil
 }
 
-func resourceReadProvider(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
-
-	id := d.Id()
+// scopesToStrings func converts the validated scopes returned by keep, where every scope is either true or the validation error, to strings
+func scopesToStrings(validatedScopes interface{}) map[string]string {
+	scopes := make(map[string]string)
+	if validatedScopes, ok := validatedScopes.(map[string]interface{}); ok {
+		for scope, result := range validatedScopes {
+			scopes[scope] = cast.ToString(result)
+		}
+	}
+	return scopes
+}
 
-	// create new request
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/providers/export", client.HostURL), nil)
-	if err != nil {
-		return diag.Errorf("cannot create request: %s", err)
+// checkRequiredScopes func returns an error listing the required scopes that were not validated
+func checkRequiredScopes(requiredScopes []interface{}, scopes map[string]string) error {
+	var failed []string
+	for _, scope := range requiredScopes {
+		scope := scope.(string)
+		result, ok := scopes[scope]
+		switch {
+		case !ok:
+			failed = append(failed, fmt.Sprintf("%s (not reported by keep)", scope))
+		case result != "true":
+			failed = append(failed, fmt.Sprintf("%s (%s)", scope, result))
+		}
 	}
 
-	// send request
-	body, err := client.doReq(req)
-	if err != nil {
-		return diag.Errorf("cannot send request: %s", err)
+	if len(failed) > 0 {
+		return fmt.Errorf("required scopes are not valid: %s", strings.Join(failed, ", "))
 	}
 
-	// unmarshal response
-	var response map[string]interface{}
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return diag.Errorf("cannot unmarshal response: %s", err)
+	return nil
+}
+
+func resourceReadProvider(h providerAuthConfig) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return readProvider(d, m.(*Client), h)
 	}
+}
 
-	installedProviders := response["installed_providers"].([]interface{})
-	if len(installedProviders) == 0 {
-		// no providers installed
-		return nil
+func readProvider(d *schema.ResourceData, client *Client, h providerAuthConfig) diag.Diagnostics {
+	id := d.Id()
+
+	installedProviders, err := getInstalledProviders(client)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	for _, provider := range installedProviders {
-		if provider.(map[string]interface{})["id"] == id {
+		if provider.ID == id {
 			// provider found
 			d.SetId(id)
-			d.Set("type", provider.(map[string]interface{})["type"])
-			d.Set("auth_config", provider.(map[string]interface{})["config"])
+			if err := h.read(d, provider); err != nil {
+				return diag.Errorf("cannot set auth config: %s", err)
+			}
+			d.Set("name", provider.Name())
+			d.Set("pulling_enabled", provider.PullingEnabled)
+			d.Set("validated_scopes", provider.Scopes())
 			return nil
 		}
 	}
 
+	// provider is not installed anymore
+	d.SetId("")
+
 	return nil
 }
 
-func resourceUpdateProvider(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+// resourceImportProvider func accepts either the id of the provider or its type and name in the form of type/name
+func resourceImportProvider(h providerAuthConfig) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		client := m.(*Client)
+
+		id := d.Id()
+		providerType, providerName, byName := strings.Cut(id, "/")
+
+		installedProviders, err := getInstalledProviders(client)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, provider := range installedProviders {
+			if (byName && provider.Type == providerType && provider.Name() == providerName) || (!byName && provider.ID == id) {
+				// typed provider resources can only import providers of their own type
+				if fixedType := h.providerType(d); fixedType != "" && fixedType != provider.Type {
+					return nil, fmt.Errorf("provider %s is of type %s, not %s", id, provider.Type, fixedType)
+				}
+
+				d.SetId(provider.ID)
+				d.Set("rollback_on_scope_failure", false)
+				// the webhook state can't be read back from keep
+				d.Set("install_webhook", false)
+				return []*schema.ResourceData{d}, nil
+			}
+		}
+
+		return nil, fmt.Errorf("provider not found: %s", id)
+	}
+}
+
+// readAuthConfig func returns the authentication config of the installed provider.
+// Secrets are not returned by keep, so they are kept as they are in the current state.
+// Non-secret values are only read for keys already in the state, or all of them when the state is empty after an import.
+func readAuthConfig(provider InstalledProvider, current map[string]interface{}) map[string]interface{} {
+	authConfig := make(map[string]interface{})
+
+	authentication, _ := provider.Details["authentication"].(map[string]interface{})
+	for key, value := range authentication {
+		if value == nil || provider.IsSensitive(key) {
+			continue
+		}
+		if _, ok := current[key]; ok || len(current) == 0 {
+			authConfig[key] = cast.ToString(value)
+		}
+	}
+
+	for key, value := range current {
+		if provider.IsSensitive(key) {
+			authConfig[key] = value
+		}
+	}
+
+	return authConfig
+}
+
+func resourceUpdateProvider(h providerAuthConfig) schema.UpdateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return updateProvider(d, m.(*Client), h)
+	}
+}
+
+func updateProvider(d *schema.ResourceData, client *Client, h providerAuthConfig) diag.Diagnostics {
 	id := d.Id()
-	providerType := d.Get("type").(string)
-	providerName := d.Get("name").(string)
-	authConfig := d.Get("auth_config").(map[string]interface{})
+	providerType := h.providerType(d)
+
+	if d.HasChanges("auth_config", "name", "pulling_enabled") {
+		// Prepare the payload for the provider update request
+		providerUpdatePayload := providerPayload(d, h)
+
+		// Marshal the payload
+		payload, err := json.Marshal(providerUpdatePayload)
+		if err != nil {
+			return diag.Errorf("cannot marshal payload: %s", err)
+		}
 
-	// Prepare the payload for the provider update request
-	providerUpdatePayload := map[string]interface{}{
-		"provider_id":   providerType,
-		"provider_name": providerName,
+		// Create a new request
+		req, err := http.NewRequest("PUT", fmt.Sprintf("%s/providers/%s", client.HostURL, id), strings.NewReader(string(payload)))
+		if err != nil {
+			return diag.Errorf("cannot create request: %s", err)
+		}
+
+		// Do the request
+		body, err := client.doReq(req)
+		if err != nil {
+			return diag.Errorf("cannot send request: %s", err)
+		}
+
+		// Parse the response
+		var response map[string]interface{}
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return diag.Errorf("cannot parse response: %s", err)
+		}
+
+		if validatedScopes, ok := response["validatedScopes"]; ok {
+			scopes := scopesToStrings(validatedScopes)
+			d.Set("validated_scopes", scopes)
+			if err := checkRequiredScopes(d.Get("required_scopes").([]interface{}), scopes); err != nil {
+				return diag.FromErr(err)
+			}
+		}
 	}
 
-	if !d.HasChange("auth_config") || !d.HasChange("name") || !d.HasChange("type") || !d.HasChange("install_webhook") {
-		return nil
+	if d.HasChange("required_scopes") {
+		// check the added scopes against the scopes keep validated on the last install or update
+		scopes := cast.ToStringMapString(d.Get("validated_scopes"))
+		if err := checkRequiredScopes(d.Get("required_scopes").([]interface{}), scopes); err != nil {
+			// keep the previous required scopes in the state, so the check runs again on the next apply
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
 	}
 
-	// Add the auth config to the payload
-	for key, value := range authConfig {
-		providerUpdatePayload[key] = value
+	if d.HasChange("install_webhook") && d.Get("install_webhook").(bool) {
+		if err := installWebhook(client, providerType, id); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	// Marshal the payload
-	payload, err := json.Marshal(providerUpdatePayload)
-	if err != nil {
-		return diag.Errorf("cannot marshal payload: %s", err)
+	d.SetId(id)
+
+	return nil
+}
+
+// providerPayload func prepares the payload of the provider installation and update requests
+func providerPayload(d *schema.ResourceData, h providerAuthConfig) map[string]interface{} {
+	// keep takes every field besides these as the authentication config of the provider
+	payload := map[string]interface{}{
+		"provider_id":     h.providerType(d),
+		"provider_name":   d.Get("name").(string),
+		"pulling_enabled": d.Get("pulling_enabled").(bool),
 	}
 
-	// Create a new request
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/providers/%s", client.HostURL, id), strings.NewReader(string(payload)))
-	if err != nil {
-		return diag.Errorf("cannot create request: %s", err)
+	// Add the auth config to the payload
+	for key, value := range h.authConfig(d) {
+		payload[key] = value
 	}
 
-	// Do the request
-	body, err := client.doReq(req)
+	return payload
+}
+
+// installWebhook func installs the webhook of the provider in the provider's side
+func installWebhook(client *Client, providerType string, id string) error {
+	// Create a new request
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/providers/install/webhook/%s/%s", client.HostURL, providerType, id), nil)
 	if err != nil {
-		return diag.Errorf("cannot send request: %s", err)
+		return fmt.Errorf("cannot create request: %s", err)
 	}
 
-	// Parse the response
-	var response map[string]interface{}
-	err = json.Unmarshal(body, &response)
+	// Do the request
+	_, err = client.doReq(req)
 	if err != nil {
-		return diag.Errorf("cannot parse response: %s", err)
+		return fmt.Errorf("cannot send request: %s", err)
 	}
 
-	d.SetId(id)
-
 	return nil
 }
@@ -0,0 +1,319 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/spf13/cast"
+)
+
+// timeUnitSeconds maps the time units of correlation rule timeframes to their length in seconds
+var timeUnitSeconds = map[string]int{
+	"seconds": 1,
+	"minutes": 60,
+	"hours":   60 * 60,
+	"days":    24 * 60 * 60,
+}
+
+func resourceCorrelationRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCreateCorrelationRule,
+		ReadContext:   resourceReadCorrelationRule,
+		UpdateContext: resourceUpdateCorrelationRule,
+		DeleteContext: resourceDeleteCorrelationRule,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportCorrelationRule,
+		},
+		Description: "Manages a correlation rule, alerts matching its CEL query within the timeframe are grouped into incidents.",
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the correlation rule",
+			},
+			"cel_query": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "CEL query alerts grouped by the rule match, e.g. `source.contains(\"prometheus\") && severity == \"critical\"`. Keep's rule editor needs the query as SQL too, so only the queries the editor can build are supported: comparisons of alert fields with literal values using `==`, `!=`, `<`, `<=`, `>`, `>=`, `in`, `contains`, `startsWith` and `endsWith`, combined with `&&`, `||` and `!`",
+				ValidateDiagFunc: validateCELSQL,
+			},
+			"timeframe": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				Description:      "Timeframe alerts are grouped within, in `time_unit`",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+			"time_unit": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "seconds",
+				Description:      "Unit of the timeframe, one of `seconds`, `minutes`, `hours` or `days` (default: seconds)",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"seconds", "minutes", "hours", "days"}, false)),
+			},
+			"grouping_criteria": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Alert fields alerts are grouped by, alerts with different values go to different incidents, e.g. `labels.cluster`",
+			},
+			"require_approve": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the incidents created by the rule must be approved manually (default: false)",
+			},
+			"resolve_on": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "never",
+				Description:      "When incidents are resolved, one of `all` (all alerts are resolved), `first` (the first alert is resolved), `last` (the last alert is resolved) or `never` (default: never)",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"all", "first", "last", "never"}, false)),
+			},
+			"create_on": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "any",
+				Description:      "When incidents are created, one of `any` (an alert matches) or `all` (every condition of the query has a matching alert) (default: any)",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"any", "all"}, false)),
+			},
+			"incident_name_template": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Template of the names of the incidents created by the rule, e.g. `{{ alert.service }} is down`. Keep names incidents after the rule when empty",
+			},
+			"created_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creator of the correlation rule",
+			},
+		},
+	}
+}
+
+func resourceCreateCorrelationRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	body, err := correlationRuleBody(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// marshal body
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return diag.Errorf("cannot marshal correlation rule body: %s", err)
+	}
+
+	// create correlation rule
+	req, err := http.NewRequest("POST", client.HostURL+"/rules", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response map[string]interface{}
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	d.SetId(cast.ToString(response["id"]))
+	setCorrelationRule(d, response)
+
+	return nil
+}
+
+func resourceReadCorrelationRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	rules, err := getCorrelationRules(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, rule := range rules {
+		if cast.ToString(rule["id"]) == d.Id() {
+			setCorrelationRule(d, rule)
+			return nil
+		}
+	}
+
+	// correlation rule is deleted
+	d.SetId("")
+
+	return nil
+}
+
+func resourceUpdateCorrelationRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	if !d.HasChanges("name", "cel_query", "timeframe", "time_unit", "grouping_criteria", "require_approve", "resolve_on", "create_on", "incident_name_template") {
+		return nil
+	}
+
+	body, err := correlationRuleBody(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// marshal body
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return diag.Errorf("cannot marshal correlation rule body: %s", err)
+	}
+
+	// update correlation rule
+	req, err := http.NewRequest("PUT", client.HostURL+"/rules/"+d.Id(), strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response map[string]interface{}
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	setCorrelationRule(d, response)
+
+	return nil
+}
+
+func resourceDeleteCorrelationRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	req, err := http.NewRequest("DELETE", client.HostURL+"/rules/"+d.Id(), nil)
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	_, err = client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	return nil
+}
+
+// resourceImportCorrelationRule func imports correlation rules by id or by name
+func resourceImportCorrelationRule(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	id := d.Id()
+
+	rules, err := getCorrelationRules(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if cast.ToString(rule["id"]) == id || cast.ToString(rule["name"]) == id {
+			d.SetId(cast.ToString(rule["id"]))
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("correlation rule not found: %s", id)
+}
+
+// getCorrelationRules func fetches the correlation rules from keep
+func getCorrelationRules(client *Client) ([]map[string]interface{}, error) {
+	// create new request
+	req, err := http.NewRequest("GET", client.HostURL+"/rules", nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	body, err := client.doReq(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response []map[string]interface{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	return response, nil
+}
+
+// correlationRuleBody func prepares the body of the correlation rule create and update requests,
+// keep evaluates the CEL query and its rule editor shows the SQL one
+func correlationRuleBody(d *schema.ResourceData) (map[string]interface{}, error) {
+	celQuery := d.Get("cel_query").(string)
+	timeUnit := d.Get("time_unit").(string)
+
+	sql, params, err := celToSQL(celQuery)
+	if err != nil {
+		return nil, fmt.Errorf("cannot translate cel_query to SQL: %s", err)
+	}
+
+	groupingCriteria := make([]string, 0)
+	for _, criteria := range d.Get("grouping_criteria").([]interface{}) {
+		groupingCriteria = append(groupingCriteria, cast.ToString(criteria))
+	}
+
+	return map[string]interface{}{
+		"ruleName": d.Get("name").(string),
+		"celQuery": celQuery,
+		"sqlQuery": map[string]interface{}{
+			"sql":    sql,
+			"params": params,
+		},
+		"timeframeInSeconds":   d.Get("timeframe").(int) * timeUnitSeconds[timeUnit],
+		"timeUnit":             timeUnit,
+		"groupingCriteria":     groupingCriteria,
+		"requireApprove":       d.Get("require_approve").(bool),
+		"resolveOn":            d.Get("resolve_on").(string),
+		"createOn":             d.Get("create_on").(string),
+		"incidentNameTemplate": d.Get("incident_name_template").(string),
+	}, nil
+}
+
+// setCorrelationRule func sets the attributes of the correlation rule as keep returns it,
+// the timeframe keep returns in seconds is converted back to the time unit
+func setCorrelationRule(d *schema.ResourceData, rule map[string]interface{}) {
+	timeframe := cast.ToInt(rule["timeframe"])
+	timeUnit := cast.ToString(rule["timeunit"])
+	if unit, ok := timeUnitSeconds[timeUnit]; !ok || timeframe%unit != 0 {
+		timeUnit = "seconds"
+	}
+
+	d.Set("name", rule["name"])
+	d.Set("cel_query", rule["definition_cel"])
+	d.Set("timeframe", timeframe/timeUnitSeconds[timeUnit])
+	d.Set("time_unit", timeUnit)
+	d.Set("grouping_criteria", cast.ToStringSlice(rule["grouping_criteria"]))
+	d.Set("require_approve", cast.ToBool(rule["require_approve"]))
+	d.Set("created_by", rule["created_by"])
+	if resolveOn := cast.ToString(rule["resolve_on"]); resolveOn != "" {
+		d.Set("resolve_on", resolveOn)
+	}
+	if createOn := cast.ToString(rule["create_on"]); createOn != "" {
+		d.Set("create_on", createOn)
+	}
+	d.Set("incident_name_template", cast.ToString(rule["incident_name_template"]))
+}
@@ -0,0 +1,245 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/spf13/cast"
+)
+
+func resourceDeduplicationRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCreateDeduplicationRule,
+		ReadContext:   resourceReadDeduplicationRule,
+		UpdateContext: resourceUpdateDeduplicationRule,
+		DeleteContext: resourceDeleteDeduplicationRule,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportDeduplicationRule,
+		},
+		CustomizeDiff: resourceDeduplicationRuleCustomizeDiff,
+		Description:   "Manages a deduplication rule, it replaces the default deduplication of the alerts of a provider.",
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the deduplication rule",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Description of the deduplication rule",
+			},
+			"provider_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Type of the provider the rule deduplicates the alerts of, e.g. `keep_provider.example.type`",
+			},
+			"provider_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "ID of the provider the rule deduplicates the alerts of, e.g. `keep_provider.example.id`. The rule applies to alerts pushed to keep without a provider when empty",
+			},
+			"fingerprint_fields": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+				},
+				Description: "Fields the fingerprint of the alerts is calculated from, alerts with the same fingerprint are deduplicated",
+			},
+			"full_deduplication": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether alerts equal to a previous alert with the same fingerprint are dropped, otherwise the previous alert is updated (default: false)",
+			},
+			"ignore_fields": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsNotEmpty),
+				},
+				Description: "Fields ignored when comparing alerts, only used with `full_deduplication`, e.g. `lastReceived`",
+			},
+			"created_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creator of the deduplication rule",
+			},
+		},
+	}
+}
+
+func resourceCreateDeduplicationRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	// marshal body
+	bodyBytes, err := json.Marshal(deduplicationRuleBody(d))
+	if err != nil {
+		return diag.Errorf("cannot marshal deduplication rule body: %s", err)
+	}
+
+	// create deduplication rule
+	req, err := http.NewRequest("POST", client.HostURL+"/deduplications", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response map[string]interface{}
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	d.SetId(cast.ToString(response["id"]))
+
+	return resourceReadDeduplicationRule(ctx, d, m)
+}
+
+func resourceReadDeduplicationRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	rules, err := getDeduplicationRules(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, rule := range rules {
+		if !rule.Default && rule.ID == d.Id() {
+			d.Set("name", rule.Name)
+			d.Set("description", rule.Description)
+			d.Set("provider_type", rule.ProviderType)
+			d.Set("provider_id", rule.ProviderID)
+			d.Set("fingerprint_fields", rule.FingerprintFields)
+			d.Set("full_deduplication", rule.FullDeduplication)
+			d.Set("ignore_fields", rule.IgnoreFields)
+			d.Set("created_by", rule.CreatedBy)
+			return nil
+		}
+	}
+
+	// deduplication rule is deleted
+	d.SetId("")
+
+	return nil
+}
+
+func resourceUpdateDeduplicationRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	if !d.HasChanges("name", "description", "provider_type", "provider_id", "fingerprint_fields", "full_deduplication", "ignore_fields") {
+		return nil
+	}
+
+	// marshal body
+	bodyBytes, err := json.Marshal(deduplicationRuleBody(d))
+	if err != nil {
+		return diag.Errorf("cannot marshal deduplication rule body: %s", err)
+	}
+
+	// update deduplication rule
+	req, err := http.NewRequest("PUT", client.HostURL+"/deduplications/"+d.Id(), strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	_, err = client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	return resourceReadDeduplicationRule(ctx, d, m)
+}
+
+func resourceDeleteDeduplicationRule(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	req, err := http.NewRequest("DELETE", client.HostURL+"/deduplications/"+d.Id(), nil)
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	_, err = client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	return nil
+}
+
+// resourceImportDeduplicationRule func imports deduplication rules by id or by name
+func resourceImportDeduplicationRule(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	id := d.Id()
+
+	rules, err := getDeduplicationRules(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if rule.ID == id || rule.Name == id {
+			if rule.Default {
+				// keep derives the default rules from the providers, they are replaced by creating a rule for the provider
+				return nil, fmt.Errorf("deduplication rule %s is a default rule of keep and can't be managed, create a keep_deduplication_rule for provider %s instead", id, rule.ProviderType)
+			}
+
+			d.SetId(rule.ID)
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("deduplication rule not found: %s", id)
+}
+
+// resourceDeduplicationRuleCustomizeDiff func rejects ignored fields without full deduplication, keep only
+// ignores fields when comparing alerts for full deduplication
+func resourceDeduplicationRuleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("full_deduplication") {
+		return nil
+	}
+
+	if !d.Get("full_deduplication").(bool) && len(d.Get("ignore_fields").([]interface{})) > 0 {
+		return fmt.Errorf("ignore_fields require full_deduplication to be enabled")
+	}
+
+	return nil
+}
+
+// deduplicationRuleBody func prepares the body of the deduplication rule create and update requests
+func deduplicationRuleBody(d *schema.ResourceData) map[string]interface{} {
+	body := map[string]interface{}{
+		"name":               d.Get("name").(string),
+		"description":        d.Get("description").(string),
+		"provider_type":      d.Get("provider_type").(string),
+		"fingerprint_fields": cast.ToStringSlice(d.Get("fingerprint_fields")),
+		"full_deduplication": d.Get("full_deduplication").(bool),
+		"ignore_fields":      cast.ToStringSlice(d.Get("ignore_fields")),
+	}
+
+	if providerID := d.Get("provider_id").(string); providerID != "" {
+		body["provider_id"] = providerID
+	}
+
+	return body
+}
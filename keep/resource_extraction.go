@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cast"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,8 +19,17 @@ func resourceExtraction() *schema.Resource {
 		ReadContext:   resourceReadExtraction,
 		UpdateContext: resourceUpdateExtraction,
 		DeleteContext: resourceDeleteExtraction,
+		CustomizeDiff: resourceExtractionCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceImportExtraction,
+		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceExtractionV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceExtractionStateUpgradeV0,
+			},
 		},
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -49,10 +60,11 @@ func resourceExtraction() *schema.Resource {
 				Description: "Attribute of the extraction",
 			},
 			"condition": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Condition of the extraction",
-				Default:     "",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "CEL condition alerts must match for the extraction to run, e.g. `source.contains(\"prometheus\")`. It is checked against the fields of keep alerts at plan time",
+				Default:          "",
+				ValidateDiagFunc: validateCELCondition,
 			},
 			"disabled": {
 				Type:     schema.TypeBool,
@@ -60,9 +72,10 @@ func resourceExtraction() *schema.Resource {
 				Default:  false,
 			},
 			"regex": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Regex of the extraction",
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "Regex of the extraction, keep evaluates it with python's re module. The named groups like `(?P<name>...)` are extracted as attributes of the alert",
+				ValidateDiagFunc: validatePythonRegex,
 			},
 			"pre": {
 				Type:        schema.TypeBool,
@@ -70,10 +83,29 @@ func resourceExtraction() *schema.Resource {
 				Default:     false,
 				Description: "Pre of the extraction",
 			},
+			"extracted_fields": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the named groups of the regex, the attributes the extraction adds to alerts",
+			},
 		},
 	}
 }
 
+// resourceExtractionCustomizeDiff func plans the fields extracted by the regex
+func resourceExtractionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("regex") {
+		return nil
+	}
+	if !d.NewValueKnown("regex") {
+		return d.SetNewComputed("extracted_fields")
+	}
+
+	groups, _ := scanPythonRegex(d.Get("regex").(string))
+	return d.SetNew("extracted_fields", groups)
+}
+
 func resourceCreateExtraction(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*Client)
 
@@ -113,8 +145,8 @@ func resourceCreateExtraction(ctx context.Context, d *schema.ResourceData, m int
 		return diag.Errorf("cannot unmarshal response: %s", err)
 	}
 
-	d.SetId(fmt.Sprintf("%f", response["id"]))
-	d.Set("id", fmt.Sprintf("%f", response["id"]))
+	d.SetId(cast.ToString(cast.ToInt(response["id"])))
+	setExtraction(d, response)
 
 	return nil
 }
@@ -122,44 +154,27 @@ func resourceCreateExtraction(ctx context.Context, d *schema.ResourceData, m int
 func resourceReadExtraction(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*Client)
 
-	id := d.Id()
-
-	req, err := http.NewRequest("GET", client.HostURL+"/extraction/", nil)
+	idInt, err := extractionID(d.Id())
 	if err != nil {
-		return diag.Errorf("cannot create request: %s", err)
+		return diag.FromErr(err)
 	}
 
-	body, err := client.doReq(req)
+	response, err := getExtractions(client)
 	if err != nil {
-		return diag.Errorf("cannot send request: %s", err)
-	}
-
-	var response []map[string]interface{}
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return diag.Errorf("cannot unmarshal response: %s", err)
-	}
-
-	idFloat, err := strconv.ParseFloat(id, 64)
-	if err != nil {
-		return diag.Errorf("cannot parse id: %s", err)
+		return diag.FromErr(err)
 	}
 
 	for _, extraction := range response {
-		if extraction["id"] == idFloat {
-			d.SetId(id)
-			d.Set("name", extraction["name"])
-			d.Set("description", extraction["description"])
-			d.Set("priority", extraction["priority"])
-			d.Set("attribute", extraction["attribute"])
-			d.Set("condition", extraction["condition"])
-			d.Set("disabled", extraction["disabled"])
-			d.Set("regex", extraction["regex"])
-			d.Set("pre", extraction["pre"])
-			break
+		if cast.ToInt(extraction["id"]) == idInt {
+			d.SetId(strconv.Itoa(idInt))
+			setExtraction(d, extraction)
+			return nil
 		}
 	}
 
+	// extraction is deleted
+	d.SetId("")
+
 	return nil
 }
 
@@ -179,7 +194,7 @@ func resourceUpdateExtraction(ctx context.Context, d *schema.ResourceData, m int
 		"pre":         d.Get("pre").(bool),
 	}
 
-	if !d.HasChange("name") || !d.HasChange("description") || !d.HasChange("priority") || !d.HasChange("attribute") || !d.HasChange("condition") || !d.HasChange("disabled") || !d.HasChange("regex") || !d.HasChange("pre") {
+	if !d.HasChanges("name", "description", "priority", "attribute", "condition", "disabled", "regex", "pre") {
 		return nil
 	}
 
@@ -209,10 +224,50 @@ func resourceUpdateExtraction(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	d.SetId(id)
+	setExtraction(d, response)
 
 	return nil
 }
 
+// getExtractions func fetches the extraction rules from keep
+func getExtractions(client *Client) ([]map[string]interface{}, error) {
+	// create new request
+	req, err := http.NewRequest("GET", client.HostURL+"/extraction/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	body, err := client.doReq(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response []map[string]interface{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	return response, nil
+}
+
+// setExtraction func sets the attributes of the extraction as keep returns it
+func setExtraction(d *schema.ResourceData, extraction map[string]interface{}) {
+	d.Set("name", extraction["name"])
+	d.Set("description", extraction["description"])
+	d.Set("priority", extraction["priority"])
+	d.Set("attribute", extraction["attribute"])
+	d.Set("condition", extraction["condition"])
+	d.Set("disabled", extraction["disabled"])
+	d.Set("regex", extraction["regex"])
+	d.Set("pre", extraction["pre"])
+
+	groups, _ := scanPythonRegex(cast.ToString(extraction["regex"]))
+	d.Set("extracted_fields", groups)
+}
+
 func resourceDeleteExtraction(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*Client)
 
@@ -230,3 +285,93 @@ func resourceDeleteExtraction(ctx context.Context, d *schema.ResourceData, m int
 
 	return nil
 }
+
+// resourceImportExtraction func imports extractions by id, ids formatted as floats like 12.000000 are accepted as well
+func resourceImportExtraction(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	id, err := extractionID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(strconv.Itoa(id))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// extractionID func parses the id of an extraction, earlier versions of the provider formatted ids as floats like 12.000000
+func extractionID(id string) (int, error) {
+	value, err := strconv.ParseFloat(id, 64)
+	if err != nil || math.IsInf(value, 0) || value != math.Trunc(value) {
+		return 0, fmt.Errorf("invalid extraction id %q, expected an integer", id)
+	}
+	return int(value), nil
+}
+
+// resourceExtractionV0 func returns the schema of the extraction resource before ids were formatted as integers
+func resourceExtractionV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the extraction",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the extraction",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the extraction",
+				Default:     "",
+			},
+			"priority": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Priority of the extraction",
+				Default:     0,
+			},
+			"attribute": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Attribute of the extraction",
+			},
+			"condition": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Condition of the extraction",
+				Default:     "",
+			},
+			"disabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"regex": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Regex of the extraction",
+			},
+			"pre": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Pre of the extraction",
+			},
+		},
+	}
+}
+
+// resourceExtractionStateUpgradeV0 func migrates ids formatted as floats like 12.000000 to integers
+func resourceExtractionStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	id, err := extractionID(cast.ToString(rawState["id"]))
+	if err != nil {
+		return nil, err
+	}
+
+	rawState["id"] = strconv.Itoa(id)
+
+	return rawState, nil
+}
@@ -0,0 +1,399 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cast"
+)
+
+func resourceExtractionSet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCreateExtractionSet,
+		ReadContext:   resourceReadExtractionSet,
+		UpdateContext: resourceUpdateExtractionSet,
+		DeleteContext: resourceDeleteExtractionSet,
+		CustomizeDiff: resourceExtractionSetCustomizeDiff,
+		Description: "Manages an ordered list of extraction rules, their priorities are assigned from their order so the first rule gets the highest priority. " +
+			"Rules are identified by their name in the set, renaming a rule replaces it. Only the rules created by the set are changed or deleted, " +
+			"other rules with the same priority as a rule of the set are reported as warnings.",
+		Schema: map[string]*schema.Schema{
+			"base_priority": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Priority of the last rule, the rules before it get increasing priorities (default: 0)",
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Extraction rules in the order they run in",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the extraction, unique in the set",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "Description of the extraction",
+						},
+						"attribute": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Attribute of the extraction",
+						},
+						"regex": {
+							Type:             schema.TypeString,
+							Required:         true,
+							Description:      "Regex of the extraction, keep evaluates it with python's re module. The named groups like `(?P<name>...)` are extracted as attributes of the alert",
+							ValidateDiagFunc: validatePythonRegex,
+						},
+						"condition": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "",
+							Description:      "CEL condition alerts must match for the extraction to run",
+							ValidateDiagFunc: validateCELCondition,
+						},
+						"disabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the extraction is disabled",
+						},
+						"pre": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Pre of the extraction",
+						},
+					},
+				},
+			},
+			"rule_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the extraction rules keyed by their name",
+			},
+			"priorities": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Priorities assigned to the extraction rules keyed by their name",
+			},
+		},
+	}
+}
+
+func resourceCreateExtractionSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	d.SetId(id.UniqueId())
+
+	ids := make(map[string]interface{})
+	for _, body := range extractionSetBodies(d.Get("rule").([]interface{}), d.Get("base_priority").(int)) {
+		name := body["name"].(string)
+		response, err := sendExtraction(client, "POST", "/extraction/", body)
+		if err != nil {
+			if len(ids) == 0 {
+				d.SetId("")
+			}
+			// keep the rules created so far, so they are deleted or updated later
+			d.Set("rule_ids", ids)
+			return diag.Errorf("cannot create extraction rule %q: %s", name, err)
+		}
+		ids[name] = cast.ToString(cast.ToInt(response["id"]))
+	}
+	d.Set("rule_ids", ids)
+
+	return resourceReadExtractionSet(ctx, d, m)
+}
+
+func resourceReadExtractionSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	extractions, err := getExtractions(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	owned := make(map[int]bool)
+	for _, ruleID := range d.Get("rule_ids").(map[string]interface{}) {
+		owned[cast.ToInt(ruleID)] = true
+	}
+
+	var rules []map[string]interface{}
+	for _, extraction := range extractions {
+		if owned[cast.ToInt(extraction["id"])] {
+			rules = append(rules, extraction)
+		}
+	}
+
+	if len(rules) == 0 {
+		// every rule of the set is deleted
+		d.SetId("")
+		return nil
+	}
+
+	// the rules of the set run in the order of their priorities
+	sort.SliceStable(rules, func(i, j int) bool {
+		return cast.ToInt(rules[i]["priority"]) > cast.ToInt(rules[j]["priority"])
+	})
+
+	ruleList := make([]map[string]interface{}, len(rules))
+	ids := make(map[string]interface{}, len(rules))
+	priorities := make(map[string]interface{}, len(rules))
+	for i, rule := range rules {
+		name := cast.ToString(rule["name"])
+		ruleList[i] = map[string]interface{}{
+			"name":        name,
+			"description": cast.ToString(rule["description"]),
+			"attribute":   cast.ToString(rule["attribute"]),
+			"regex":       cast.ToString(rule["regex"]),
+			"condition":   cast.ToString(rule["condition"]),
+			"disabled":    cast.ToBool(rule["disabled"]),
+			"pre":         cast.ToBool(rule["pre"]),
+		}
+		ids[name] = cast.ToString(cast.ToInt(rule["id"]))
+		priorities[name] = cast.ToInt(rule["priority"])
+	}
+
+	if err := d.Set("rule", ruleList); err != nil {
+		return diag.Errorf("cannot set rule: %s", err)
+	}
+	d.Set("rule_ids", ids)
+	d.Set("priorities", priorities)
+
+	return extractionSetConflicts(rules, extractions, owned)
+}
+
+// extractionSetConflicts func warns about the rules outside of the set with the same priority as a rule of the set,
+// keep doesn't define which of them runs first
+func extractionSetConflicts(rules []map[string]interface{}, extractions []map[string]interface{}, owned map[int]bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, extraction := range extractions {
+		if owned[cast.ToInt(extraction["id"])] {
+			continue
+		}
+
+		for _, rule := range rules {
+			if cast.ToInt(rule["priority"]) != cast.ToInt(extraction["priority"]) {
+				continue
+			}
+
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Extraction rule %q conflicts with rule %q of the set", cast.ToString(extraction["name"]), cast.ToString(rule["name"])),
+				Detail: fmt.Sprintf("Rule %q (id %d) is not managed by the set and has the same priority %d, which of them runs first is undefined. "+
+					"Change base_priority or the priority of the other rule.", cast.ToString(extraction["name"]), cast.ToInt(extraction["id"]), cast.ToInt(rule["priority"])),
+			})
+		}
+	}
+	return diags
+}
+
+func resourceUpdateExtractionSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	if !d.HasChanges("rule", "base_priority") {
+		return nil
+	}
+
+	oldIDs, _ := d.GetChange("rule_ids")
+	oldRules, newRules := d.GetChange("rule")
+	oldBase, newBase := d.GetChange("base_priority")
+
+	previous := make(map[string]map[string]interface{})
+	for _, body := range extractionSetBodies(oldRules.([]interface{}), oldBase.(int)) {
+		previous[body["name"].(string)] = body
+	}
+	desired := extractionSetBodies(newRules.([]interface{}), newBase.(int))
+
+	ids := make(map[string]interface{})
+	for name, ruleID := range oldIDs.(map[string]interface{}) {
+		ids[name] = ruleID
+	}
+
+	// delete the rules removed from the set first, so the remaining ones never collide with them
+	for name, ruleID := range oldIDs.(map[string]interface{}) {
+		if hasRule(desired, name) {
+			continue
+		}
+		if err := deleteExtraction(client, cast.ToString(ruleID)); err != nil {
+			d.Set("rule_ids", ids)
+			return diag.Errorf("cannot delete extraction rule %q: %s", name, err)
+		}
+		delete(ids, name)
+	}
+
+	for _, body := range desired {
+		name := body["name"].(string)
+
+		ruleID, ok := ids[name]
+		if !ok {
+			response, err := sendExtraction(client, "POST", "/extraction/", body)
+			if err != nil {
+				d.Set("rule_ids", ids)
+				return diag.Errorf("cannot create extraction rule %q: %s", name, err)
+			}
+			ids[name] = cast.ToString(cast.ToInt(response["id"]))
+			continue
+		}
+
+		if reflect.DeepEqual(previous[name], body) {
+			// the rule and its position in the set are unchanged
+			continue
+		}
+		if _, err := sendExtraction(client, "PUT", "/extraction/"+cast.ToString(ruleID), body); err != nil {
+			d.Set("rule_ids", ids)
+			return diag.Errorf("cannot update extraction rule %q: %s", name, err)
+		}
+	}
+	d.Set("rule_ids", ids)
+
+	return resourceReadExtractionSet(ctx, d, m)
+}
+
+func resourceDeleteExtractionSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	for name, ruleID := range d.Get("rule_ids").(map[string]interface{}) {
+		if err := deleteExtraction(client, cast.ToString(ruleID)); err != nil {
+			return diag.Errorf("cannot delete extraction rule %q: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// resourceExtractionSetCustomizeDiff func plans the priorities of the rules and checks their names are unique
+func resourceExtractionSetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.GetRawConfig().GetAttr("rule").IsWhollyKnown() || !d.NewValueKnown("base_priority") {
+		// priorities are known once the rules are
+		d.SetNewComputed("priorities")
+		if d.Id() != "" {
+			d.SetNewComputed("rule_ids")
+		}
+		return nil
+	}
+
+	priorities := make(map[string]interface{})
+	for _, body := range extractionSetBodies(d.Get("rule").([]interface{}), d.Get("base_priority").(int)) {
+		name := body["name"].(string)
+		if _, ok := priorities[name]; ok {
+			return fmt.Errorf("rule name %q is used more than once, rule names must be unique in the set", name)
+		}
+		priorities[name] = body["priority"]
+	}
+
+	if !reflect.DeepEqual(priorities, d.Get("priorities")) {
+		if err := d.SetNew("priorities", priorities); err != nil {
+			return err
+		}
+	}
+
+	// rules added to or removed from the set change the ids
+	ids := d.Get("rule_ids").(map[string]interface{})
+	changed := len(ids) != len(priorities)
+	for name := range priorities {
+		if _, ok := ids[name]; !ok {
+			changed = true
+		}
+	}
+	if changed {
+		return d.SetNewComputed("rule_ids")
+	}
+
+	return nil
+}
+
+// extractionSetBodies func returns the request bodies of the rules of the set with their priorities,
+// the first rule gets the highest priority and the last one gets the base priority
+func extractionSetBodies(rules []interface{}, basePriority int) []map[string]interface{} {
+	bodies := make([]map[string]interface{}, len(rules))
+	for i, r := range rules {
+		rule, _ := r.(map[string]interface{})
+		bodies[i] = map[string]interface{}{
+			"name":        cast.ToString(rule["name"]),
+			"description": cast.ToString(rule["description"]),
+			"priority":    basePriority + len(rules) - 1 - i,
+			"attribute":   cast.ToString(rule["attribute"]),
+			"condition":   cast.ToString(rule["condition"]),
+			"disabled":    cast.ToBool(rule["disabled"]),
+			"regex":       cast.ToString(rule["regex"]),
+			"pre":         cast.ToBool(rule["pre"]),
+		}
+	}
+	return bodies
+}
+
+// hasRule func returns whether the bodies have a rule with the name
+func hasRule(bodies []map[string]interface{}, name string) bool {
+	for _, body := range bodies {
+		if body["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sendExtraction func creates or updates an extraction rule and returns it as keep responds with it
+func sendExtraction(client *Client, method string, path string, body map[string]interface{}) (map[string]interface{}, error) {
+	// marshal body
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal extraction body: %s", err)
+	}
+
+	// create new request
+	req, err := http.NewRequest(method, client.HostURL+path, strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doReq(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response map[string]interface{}
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	return response, nil
+}
+
+// deleteExtraction func deletes an extraction rule
+func deleteExtraction(client *Client, id string) error {
+	// create new request
+	req, err := http.NewRequest("DELETE", client.HostURL+"/extraction/"+id, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	_, err = client.doReq(req)
+	if err != nil {
+		return fmt.Errorf("cannot send request: %s", err)
+	}
+
+	return nil
+}
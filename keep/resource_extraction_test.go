@@ -0,0 +1,158 @@
+package keep
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceUpdateExtractionSingleField(t *testing.T) {
+	extraction := map[string]interface{}{
+		"id":          12,
+		"name":        "instance_host",
+		"description": "host of the instance",
+		"priority":    0,
+		"attribute":   "labels.instance",
+		"condition":   "",
+		"disabled":    false,
+		"regex":       "(?P<host>[a-z0-9-]+):",
+		"pre":         false,
+	}
+	updated := make(map[string]interface{})
+	for key, value := range extraction {
+		updated[key] = value
+	}
+	updated["regex"] = "(?P<host>[a-z0-9-]+):(?P<port>\\d+)"
+	// keep answers with the extraction as it stored it
+	updated["description"] = "host and port of the instance"
+
+	mock := newMockKeep(t, map[string]interface{}{
+		"GET /extraction/":   []interface{}{extraction},
+		"PUT /extraction/12": updated,
+	})
+
+	r := resourceExtraction()
+	d := r.Data(&terraform.InstanceState{ID: "12"})
+	if diags := resourceReadExtraction(context.Background(), d, mock.client()); diags.HasError() {
+		t.Fatalf("cannot read extraction: %v", diags)
+	}
+	state := d.State()
+	mock.reset()
+
+	// only the regex changes
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "instance_host",
+		"description": "host of the instance",
+		"attribute":   "labels.instance",
+		"regex":       "(?P<host>[a-z0-9-]+):(?P<port>\\d+)",
+	})
+	diff, err := r.Diff(context.Background(), state, config, mock.client())
+	if err != nil {
+		t.Fatalf("cannot diff extraction: %s", err)
+	}
+
+	newState, diags := r.Apply(context.Background(), state, diff, mock.client())
+	if diags.HasError() {
+		t.Fatalf("cannot update extraction: %v", diags)
+	}
+
+	puts := mock.received("PUT")
+	if len(puts) != 1 {
+		t.Fatalf("expected 1 PUT request, got %d", len(puts))
+	}
+	if puts[0].Path != "/extraction/12" {
+		t.Errorf("expected PUT /extraction/12, got PUT %s", puts[0].Path)
+	}
+	if regex := puts[0].Body["regex"]; regex != updated["regex"] {
+		t.Errorf("expected the new regex in the body, got %v", regex)
+	}
+
+	if newState.ID != "12" {
+		t.Errorf("expected the id to stay 12, got %s", newState.ID)
+	}
+	for key, expected := range map[string]string{
+		"regex":              "(?P<host>[a-z0-9-]+):(?P<port>\\d+)",
+		"description":        "host and port of the instance",
+		"extracted_fields.#": "2",
+		"extracted_fields.1": "port",
+	} {
+		if value := newState.Attributes[key]; value != expected {
+			t.Errorf("expected %s to be read back as %q, got %q", key, expected, value)
+		}
+	}
+}
+
+func TestExtractionID(t *testing.T) {
+	for _, tc := range []struct {
+		id       string
+		expected int
+		valid    bool
+	}{
+		{id: "12", expected: 12, valid: true},
+		{id: "12.000000", expected: 12, valid: true},
+		{id: "0", expected: 0, valid: true},
+		{id: "1e3", expected: 1000, valid: true},
+		{id: "12.5", valid: false},
+		{id: "Inf", valid: false},
+		{id: "NaN", valid: false},
+		{id: "instance_host", valid: false},
+		{id: "", valid: false},
+	} {
+		t.Run(tc.id, func(t *testing.T) {
+			id, err := extractionID(tc.id)
+			if !tc.valid {
+				if err == nil {
+					t.Errorf("expected %q to be rejected, got %d", tc.id, id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected %q to be valid, got %s", tc.id, err)
+			}
+			if id != tc.expected {
+				t.Errorf("expected %q to be parsed as %d, got %d", tc.id, tc.expected, id)
+			}
+		})
+	}
+}
+
+func TestResourceExtractionStateUpgradeV0(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		id       interface{}
+		expected string
+		valid    bool
+	}{
+		{name: "float id", id: "12.000000", expected: "12", valid: true},
+		{name: "integer id", id: "12", expected: "12", valid: true},
+		{name: "fractional id", id: "12.500000", valid: false},
+		{name: "missing id", id: nil, valid: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rawState := map[string]interface{}{
+				"id":    tc.id,
+				"name":  "instance_host",
+				"regex": "(?P<host>[a-z0-9-]+):",
+			}
+
+			upgraded, err := resourceExtractionStateUpgradeV0(context.Background(), rawState, nil)
+			if !tc.valid {
+				if err == nil {
+					t.Errorf("expected id %v to be rejected, got %v", tc.id, upgraded["id"])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cannot upgrade state: %s", err)
+			}
+			if upgraded["id"] != tc.expected {
+				t.Errorf("expected id %q, got %v", tc.expected, upgraded["id"])
+			}
+			// the other attributes are kept as they are
+			if upgraded["name"] != "instance_host" || upgraded["regex"] != "(?P<host>[a-z0-9-]+):" {
+				t.Errorf("expected the other attributes to be kept, got %v", upgraded)
+			}
+		})
+	}
+}
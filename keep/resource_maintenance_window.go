@@ -0,0 +1,339 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/spf13/cast"
+)
+
+func resourceMaintenanceWindow() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCreateMaintenanceWindow,
+		ReadContext:   resourceReadMaintenanceWindow,
+		UpdateContext: resourceUpdateMaintenanceWindow,
+		DeleteContext: resourceDeleteMaintenanceWindow,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Description: "Manages a maintenance window, alerts matching its CEL query during the window are suppressed or dropped. " +
+			"Windows are one-off, keep has no recurring maintenance windows, so recurring maintenance needs a window per occurrence, e.g. with `for_each` over their start times.",
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the maintenance window",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Description of the maintenance window",
+			},
+			"cel_query": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "CEL query alerts affected by the maintenance window match, e.g. `service == \"checkout\"`",
+				ValidateDiagFunc: validateCELCondition,
+			},
+			"start_time": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "Start of the maintenance window as an RFC3339 time, e.g. `2024-06-01T22:00:00Z`. The window happens once",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+				DiffSuppressFunc: suppressEquivalentTime,
+			},
+			"duration": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Duration of the maintenance window, e.g. 2h. One of `duration` or `end_time` must be specified",
+				ValidateDiagFunc: validateDuration,
+				DiffSuppressFunc: suppressEquivalentDuration,
+				ExactlyOneOf:     []string{"duration", "end_time"},
+			},
+			"end_time": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "End of the maintenance window as an RFC3339 time. One of `duration` or `end_time` must be specified",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+				DiffSuppressFunc: suppressEquivalentTime,
+				ExactlyOneOf:     []string{"duration", "end_time"},
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the maintenance window is enabled (default: true)",
+			},
+			"suppress": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether alerts are kept with a suppressed status instead of being dropped during the maintenance window (default: false)",
+			},
+			"created_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creator of the maintenance window",
+			},
+		},
+	}
+}
+
+func resourceCreateMaintenanceWindow(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	body, err := maintenanceWindowBody(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// marshal body
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return diag.Errorf("cannot marshal maintenance window body: %s", err)
+	}
+
+	// create maintenance window
+	req, err := http.NewRequest("POST", client.HostURL+"/maintenance", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response map[string]interface{}
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	d.SetId(cast.ToString(cast.ToInt(response["id"])))
+	setMaintenanceWindow(d, response)
+
+	return nil
+}
+
+func resourceReadMaintenanceWindow(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	idInt := cast.ToInt(d.Id())
+
+	req, err := http.NewRequest("GET", client.HostURL+"/maintenance", nil)
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	body, err := client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	var response []map[string]interface{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	for _, window := range response {
+		if cast.ToInt(window["id"]) == idInt {
+			d.SetId(strconv.Itoa(idInt))
+			setMaintenanceWindow(d, window)
+			return nil
+		}
+	}
+
+	// maintenance window is deleted
+	d.SetId("")
+
+	return nil
+}
+
+func resourceUpdateMaintenanceWindow(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	if !d.HasChanges("name", "description", "cel_query", "start_time", "duration", "end_time", "enabled", "suppress") {
+		return nil
+	}
+
+	body, err := maintenanceWindowBody(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// marshal body
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return diag.Errorf("cannot marshal maintenance window body: %s", err)
+	}
+
+	// update maintenance window
+	req, err := http.NewRequest("PUT", client.HostURL+"/maintenance/"+d.Id(), strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response map[string]interface{}
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	setMaintenanceWindow(d, response)
+
+	return nil
+}
+
+func resourceDeleteMaintenanceWindow(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	req, err := http.NewRequest("DELETE", client.HostURL+"/maintenance/"+d.Id(), nil)
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	_, err = client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	return nil
+}
+
+// maintenanceWindowBody func prepares the body of the maintenance window create and update requests,
+// keep takes the length of the window in seconds
+func maintenanceWindowBody(d *schema.ResourceData) (map[string]interface{}, error) {
+	startTime, err := parseKeepTime(d.Get("start_time").(string))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse start_time: %s", err)
+	}
+
+	var duration time.Duration
+	if v := d.Get("duration").(string); v != "" {
+		duration, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse duration: %s", err)
+		}
+	} else {
+		endTime, err := parseKeepTime(d.Get("end_time").(string))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse end_time: %s", err)
+		}
+		duration = endTime.Sub(startTime)
+	}
+	if duration < time.Second {
+		return nil, fmt.Errorf("maintenance window must last at least 1s, end_time must be after start_time")
+	}
+
+	return map[string]interface{}{
+		"name":             d.Get("name").(string),
+		"description":      d.Get("description").(string),
+		"cel_query":        d.Get("cel_query").(string),
+		"start_time":       startTime.UTC().Format(time.RFC3339),
+		"duration_seconds": int(duration.Seconds()),
+		"enabled":          d.Get("enabled").(bool),
+		"suppress":         d.Get("suppress").(bool),
+	}, nil
+}
+
+// setMaintenanceWindow func sets the attributes of the maintenance window as keep returns it.
+// The end of the window is read back the way it is configured, either as a duration or as an end time.
+func setMaintenanceWindow(d *schema.ResourceData, window map[string]interface{}) {
+	d.Set("name", window["name"])
+	d.Set("description", window["description"])
+	d.Set("cel_query", window["cel_query"])
+	d.Set("enabled", window["enabled"])
+	d.Set("suppress", window["suppress"])
+	d.Set("created_by", window["created_by"])
+
+	startTime, err := parseKeepTime(cast.ToString(window["start_time"]))
+	if err != nil {
+		return
+	}
+	d.Set("start_time", startTime.Format(time.RFC3339))
+
+	duration := time.Duration(cast.ToInt(window["duration_seconds"])) * time.Second
+	if d.Get("end_time").(string) != "" {
+		d.Set("end_time", startTime.Add(duration).Format(time.RFC3339))
+	} else {
+		d.Set("duration", duration.String())
+	}
+}
+
+// parseKeepTime func parses RFC3339 times, times keep returns without a time zone are in UTC
+func parseKeepTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05.999999999", value, time.UTC)
+}
+
+// suppressEquivalentTime func suppresses the diff between times written differently, e.g. in other time zones
+func suppressEquivalentTime(k, old, new string, d *schema.ResourceData) bool {
+	oldTime, err := parseKeepTime(old)
+	if err != nil {
+		return false
+	}
+	newTime, err := parseKeepTime(new)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
+
+// validateDuration func validates that the value is a positive duration
+func validateDuration(v interface{}, path cty.Path) diag.Diagnostics {
+	duration, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid duration",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+	if duration < time.Second {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid duration",
+			Detail:        fmt.Sprintf("duration must be at least 1s, got %s", duration),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
+
+// suppressEquivalentDuration func suppresses the diff between durations written differently, e.g. 5m and 300s
+func suppressEquivalentDuration(k, old, new string, d *schema.ResourceData) bool {
+	oldDuration, err := time.ParseDuration(old)
+	if err != nil {
+		return false
+	}
+	newDuration, err := time.ParseDuration(new)
+	if err != nil {
+		return false
+	}
+	return oldDuration == newDuration
+}
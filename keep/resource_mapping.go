@@ -1,16 +1,24 @@
 package keep
 
 import (
+	"bufio"
+	"bytes"
 	"context"
-	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
-	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/spf13/cast"
 )
 
@@ -20,6 +28,13 @@ func resourceMapping() *schema.Resource {
 		ReadContext:   resourceReadMapping,
 		UpdateContext: resourceUpdateMapping,
 		DeleteContext: resourceDeleteMapping,
+		CustomizeDiff: resourceMappingCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			// large mappings take longer to upload and download than the timeout of the provider
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -42,15 +57,97 @@ func resourceMapping() *schema.Resource {
 				Description: "List of matchers",
 			},
 			"priority": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "Priority of the mapping",
-				Default:     0,
+				Type:     schema.TypeInt,
+				Optional: true,
+				Description: "Priority of the mapping. Mappings matching alerts on the same matchers and enriching them with the same attributes need different priorities, " +
+					"conflicts are reported as warnings when the mapping is applied or refreshed since terraform can't show warnings in the plan",
+				Default: 0,
+			},
+			"type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "csv",
+				Description:      "Type of the mapping, `csv` enriches alerts from the rows of the mapping and `topology` from the service topology (default: csv)",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"csv", "topology"}, false)),
 			},
 			"mapping_file_path": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path of the mapping file, conflicts with `rows`. Only allowed for `csv` mappings",
+				ConflictsWith: []string{"rows"},
+			},
+			"format": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Format of the mapping file, one of `csv`, `tsv`, `json` (an array of objects) or `yaml` (a list of objects). Detected from the file extension when not set, falling back to `csv`",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(mappingFileFormats, false)),
+				ConflictsWith:    []string{"rows"},
+			},
+			"delimiter": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Delimiter of `csv` and `tsv` mapping files, defaults to a comma for `csv` and a tab for `tsv`",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 1)),
+				ConflictsWith:    []string{"rows"},
+			},
+			"rows": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{Type: schema.TypeString},
+				},
+				Description:   "Rows of the mapping, every row maps the matcher columns to the enrichment columns, conflicts with `mapping_file_path`. Only allowed for `csv` mappings",
+				ConflictsWith: []string{"mapping_file_path"},
+			},
+			"rows_hash": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Path of the mapping file",
+				Computed:    true,
+				Description: "Hash of the rows of the mapping, regardless of their order. Changes made to the rows in keep show up as a diff",
+			},
+			"track_row_changes": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Keep a short hash of every row in `row_fingerprints`, so the plan shows which rows are added, removed or changed in keep. It adds an entry per row to the state and the plan, leave it disabled for large mappings (default: false)",
+			},
+			"row_fingerprints": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Short hash of every row of the mapping keyed by the values of its matchers, only set when `track_row_changes` is enabled",
+			},
+			"attributes": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Attributes alerts are enriched with, the columns of the mapping that are not matchers",
+			},
+			"row_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of rows of the mapping",
+			},
+			"file_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the mapping file, empty for inline rows",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creation time of the mapping",
+			},
+			"created_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creator of the mapping",
+			},
+			"updated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time of the last update of the mapping",
 			},
 		},
 	}
@@ -58,70 +155,236 @@ func resourceMapping() *schema.Resource {
 
 func resourceCreateMapping(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*Client)
-	mappingFilePath := d.Get("mapping_file_path").(string)
 
-	// read file from mappingFilePath it should be a file path and csv file
-	fInfo, err := os.Stat(mappingFilePath)
+	conflicts, err := plannedMappingConflicts(ctx, d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	body, digest, err := mappingRequestBody(d, "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// create mapping, the rows are streamed from the mapping file while the request is sent
+	req, err := http.NewRequestWithContext(ctx, "POST", client.HostURL+"/mapping/", body)
+	if err != nil {
+		body.Close()
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doLongReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response map[string]interface{}
+	err = json.Unmarshal(respBody, &response)
 	if err != nil {
-		return diag.Errorf("mapping file not found: %s", mappingFilePath)
-	} else if fInfo.IsDir() {
-		return diag.Errorf("mapping file is a directory: %s", mappingFilePath)
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	d.SetId(cast.ToString(cast.ToInt(response["id"])))
+	setDigest(d, digest.wait())
+
+	_, _, diags := readMapping(ctx, d, client)
+	return append(conflicts, diags...)
+}
+
+func resourceReadMapping(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	mapping, mappings, diags := readMapping(ctx, d, m.(*Client))
+	if mapping == nil {
+		return diags
 	}
 
-	file, err := os.OpenFile(mappingFilePath, os.O_RDONLY, 0644)
+	return mappingConflicts(*mapping, mappings)
+}
+
+// readMapping func sets the attributes of the mapping as keep returns it, along with the other mappings of keep.
+// The mapping is nil when it is deleted.
+func readMapping(ctx context.Context, d *schema.ResourceData, client *Client) (*Mapping, []Mapping, diag.Diagnostics) {
+	idInt := cast.ToInt(d.Id())
+	inlineRows := d.Get("mapping_file_path").(string) == ""
+	trackRows := d.Get("track_row_changes").(bool)
+
+	// the rows of the mapping are digested while they are received, they are only kept when they are inline rows.
+	// Keep may send the id of a mapping after its rows, so they are digested until it is known.
+	digests := make(map[int]*mappingDigest)
+	rows := make(map[int][]map[string]string)
+	mappings, err := getMappings(ctx, client, func(index int, mapping Mapping) func(row map[string]string) {
+		if mapping.ID != 0 && mapping.ID != idInt {
+			return nil
+		}
+
+		matchers := mappingMatchers(d)
+		if mapping.Matchers != nil {
+			matchers = slices.Clone(mapping.Matchers)
+			sort.Strings(matchers)
+		}
+		digest := newMappingDigest(matchers, trackRows)
+		digests[index] = digest
+
+		return func(row map[string]string) {
+			digest.add(row)
+			if inlineRows {
+				rows[index] = append(rows[index], row)
+			}
+		}
+	})
 	if err != nil {
-		return diag.Errorf("cannot open file: %s", mappingFilePath)
+		return nil, nil, diag.FromErr(err)
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	for index, mapping := range mappings {
+		if mapping.ID != idInt {
+			continue
+		}
+
+		d.SetId(strconv.Itoa(mapping.ID))
+		d.Set("name", mapping.Name)
+		d.Set("description", mapping.Description)
+		d.Set("matchers", mapping.Matchers)
+		d.Set("priority", mapping.Priority)
+		d.Set("file_name", mapping.FileName)
+		d.Set("attributes", mapping.Attributes)
+		d.Set("created_at", mapping.CreatedAt)
+		d.Set("created_by", mapping.CreatedBy)
+		d.Set("updated_at", mapping.UpdatedAt)
+		// row tracking is a setting of the provider, it is kept as configured
+		d.Set("track_row_changes", trackRows)
+		if mapping.Type != "" {
+			d.Set("type", mapping.Type)
+		}
+
+		// rows are not always returned by keep, drift is only detected when they are
+		if d.Get("type").(string) == "topology" {
+			setDigest(d, nil)
+		} else if mapping.hasRows {
+			if inlineRows {
+				d.Set("rows", rows[index])
+			}
+			d.Set("row_count", mapping.RowCount())
+			setDigest(d, digests[index])
+		}
+
+		return &mapping, mappings, nil
+	}
+
+	// mapping is deleted
+	d.SetId("")
+
+	return nil, mappings, nil
+}
+
+// plannedMappingConflicts func returns the conflicts of the mapping as planned with the other mappings of keep.
+// Terraform can't show warnings at plan time, so they are reported before the mapping is sent instead.
+func plannedMappingConflicts(ctx context.Context, d *schema.ResourceData, client *Client) (diag.Diagnostics, error) {
+	mappings, err := getMappings(ctx, client, nil)
 	if err != nil {
-		return diag.Errorf("Error reading CSV file: %s", err)
+		return nil, err
 	}
 
-	headers := records[0]
-	records = records[1:]
+	var attributes []string
+	for _, attribute := range d.Get("attributes").(*schema.Set).List() {
+		attributes = append(attributes, attribute.(string))
+	}
+
+	planned := Mapping{
+		ID:         cast.ToInt(d.Id()),
+		Name:       d.Get("name").(string),
+		Priority:   d.Get("priority").(int),
+		Matchers:   mappingMatchers(d),
+		Attributes: attributes,
+	}
 
-	rows := make([]map[string]string, len(records))
-	for i, record := range records {
-		row := make(map[string]string)
-		for j, cell := range record {
-			row[headers[j]] = cell
+	return mappingConflicts(planned, mappings), nil
+}
+
+// mappingConflicts func warns about the mappings with overlapping matchers that enrich alerts with the same attributes
+// at the same priority, keep doesn't define which of them wins
+func mappingConflicts(mapping Mapping, mappings []Mapping) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, other := range mappings {
+		if other.ID == mapping.ID || other.Priority != mapping.Priority {
+			continue
 		}
-		rows[i] = row
+
+		matchers := intersectStrings(mapping.Matchers, other.Matchers)
+		attributes := intersectStrings(mapping.Attributes, other.Attributes)
+		if len(matchers) == 0 || len(attributes) == 0 {
+			continue
+		}
+
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Mapping %q conflicts with mapping %q", mapping.Name, other.Name),
+			Detail: fmt.Sprintf("Both mappings match alerts on %s and enrich them with %s at priority %d, "+
+				"which of the values alerts get is undefined. Give one of the mappings a different priority.",
+				strings.Join(matchers, ", "), strings.Join(attributes, ", "), mapping.Priority),
+		})
 	}
+	return diags
+}
 
-	matchers := d.Get("matchers").(*schema.Set).List()
-	//convert matchers to string array
-	matchersStr := make([]string, len(matchers))
-	for i, matcher := range matchers {
-		matchersStr[i] = matcher.(string)
+// intersectStrings func returns the sorted values found in both lists
+func intersectStrings(a []string, b []string) []string {
+	var common []string
+	for _, value := range a {
+		if slices.Contains(b, value) && !slices.Contains(common, value) {
+			common = append(common, value)
+		}
 	}
+	sort.Strings(common)
+	return common
+}
 
-	body := map[string]interface{}{
-		"name":        d.Get("name").(string),
-		"description": d.Get("description").(string),
-		"matchers":    matchersStr,
-		"priority":    d.Get("priority").(int),
-		"rows":        rows,
-		"file_name":   fInfo.Name(),
+// setDigest func sets the hash and the row fingerprints of the mapping rows
+func setDigest(d *schema.ResourceData, digest *mappingDigest) {
+	if digest == nil {
+		// topology mappings have no rows, clear the ones left from a csv mapping
+		d.Set("rows", nil)
+		d.Set("row_count", 0)
+		d.Set("rows_hash", "")
+		d.Set("row_fingerprints", nil)
+		d.Set("file_name", "")
+		return
 	}
 
-	// marshal body
-	bodyBytes, err := json.Marshal(body)
+	d.Set("rows_hash", digest.hash())
+	d.Set("row_fingerprints", digest.fingerprints)
+}
+
+func resourceUpdateMapping(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	id := d.Id()
+
+	if !d.HasChanges("name", "description", "matchers", "priority", "type", "mapping_file_path", "format", "delimiter", "rows", "rows_hash") {
+		// nothing keep knows about has changed
+		return nil
+	}
+
+	conflicts, err := plannedMappingConflicts(ctx, d, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	body, digest, err := mappingRequestBody(d, id)
 	if err != nil {
-		return diag.Errorf("cannot marshal mapping body: %s", err)
+		return diag.FromErr(err)
 	}
 
-	// create mapping
-	req, err := http.NewRequest("POST", client.HostURL+"/mapping/", strings.NewReader(string(bodyBytes)))
+	// update mapping, the rows are streamed from the mapping file while the request is sent
+	req, err := http.NewRequestWithContext(ctx, "PUT", client.HostURL+"/mapping/"+id, body)
 	if err != nil {
+		body.Close()
 		return diag.Errorf("cannot create request: %s", err)
 	}
 
 	// send request
-	respBody, err := client.doReq(req)
+	respBody, err := client.doLongReq(req)
 	if err != nil {
 		return diag.Errorf("cannot send request: %s", err)
 	}
@@ -133,188 +396,282 @@ func resourceCreateMapping(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("cannot unmarshal response: %s", err)
 	}
 
-	d.SetId(cast.ToString(cast.ToInt(response["id"])))
+	// the mapping is updated in place, its id doesn't change
+	d.SetId(id)
+	setDigest(d, digest.wait())
 
-	return nil
+	_, _, diags := readMapping(ctx, d, client)
+	return append(conflicts, diags...)
 }
 
-func resourceReadMapping(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceDeleteMapping(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*Client)
 
 	id := d.Id()
 
-	req, err := http.NewRequest("GET", client.HostURL+"/mapping/", nil)
+	req, err := http.NewRequest("DELETE", client.HostURL+"/mapping/"+id, nil)
 	if err != nil {
 		return diag.Errorf("cannot create request: %s", err)
 	}
 
-	body, err := client.doReq(req)
+	_, err = client.doReq(req)
 	if err != nil {
 		return diag.Errorf("cannot send request: %s", err)
 	}
 
-	var response []map[string]interface{}
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return diag.Errorf("cannot unmarshal response: %s", err)
-	}
+	return nil
+}
 
-	idInt := cast.ToInt(id)
+// resourceGetter is implemented by both schema.ResourceData and schema.ResourceDiff
+type resourceGetter interface {
+	Get(key string) interface{}
+}
 
-	for _, mapping := range response {
-		if mapping["id"] == idInt {
-			d.SetId(cast.ToString(idInt))
-			d.Set("name", mapping["name"])
-			d.Set("description", mapping["description"])
-			d.Set("matchers", mapping["matchers"])
-			d.Set("priority", mapping["priority"])
-			d.Set("file_name", mapping["file_name"])
-			break
-		}
+// openMapping func returns a reader over the rows of the mapping, either from the mapping file or from the inline rows,
+// along with the name of the mapping file which is empty for inline rows
+func openMapping(d resourceGetter) (mappingReader, string, error) {
+	mappingFilePath := d.Get("mapping_file_path").(string)
+	if mappingFilePath == "" {
+		reader, err := newInlineMappingReader(d.Get("rows").([]interface{}))
+		return reader, "", err
 	}
 
-	return nil
+	reader, err := openMappingFile(mappingFilePath, d.Get("format").(string), d.Get("delimiter").(string))
+	return reader, filepath.Base(mappingFilePath), err
 }
 
-func resourceUpdateMapping(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
-
-	id := d.Id()
+// mappingRequestBody func returns the body of the mapping create and update requests. The rows are read from
+// the mapping file while the body is read, so large mappings are never held in memory as a whole.
+// The digest of the rows is complete once the body is read, topology mappings have none.
+func mappingRequestBody(d *schema.ResourceData, id string) (io.ReadCloser, *streamedDigest, error) {
+	body := mappingBody(d)
+	if id != "" {
+		body["id"] = cast.ToInt(id)
+	}
 
-	req, err := http.NewRequest("GET", client.HostURL+"/mapping/", nil)
-	if err != nil {
-		return diag.Errorf("cannot create request: %s", err)
+	if d.Get("type").(string) == "topology" {
+		// topology mappings have no rows
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot marshal mapping body: %s", err)
+		}
+		return io.NopCloser(bytes.NewReader(bodyBytes)), &streamedDigest{}, nil
 	}
 
-	body, err := client.doReq(req)
+	reader, fileName, err := openMapping(d)
 	if err != nil {
-		return diag.Errorf("cannot send request: %s", err)
+		return nil, nil, err
+	}
+	if fileName != "" {
+		body["file_name"] = fileName
 	}
 
-	var response []map[string]interface{}
-	err = json.Unmarshal(body, &response)
+	bodyBytes, err := json.Marshal(body)
 	if err != nil {
-		return diag.Errorf("cannot unmarshal response: %s", err)
+		reader.Close()
+		return nil, nil, fmt.Errorf("cannot marshal mapping body: %s", err)
 	}
 
-	if len(response) == 0 {
-		return diag.Errorf("no mapping found")
-	}
+	pr, pw := io.Pipe()
+	digest := &streamedDigest{mappingDigest: newMappingDigest(mappingMatchers(d), d.Get("track_row_changes").(bool)), done: make(chan struct{})}
+	go func() {
+		defer close(digest.done)
+		defer reader.Close()
+		pw.CloseWithError(writeMappingBody(pw, bodyBytes, reader, digest.mappingDigest))
+	}()
 
-	// check if mapping exists
-	for _, mapping := range response {
-		if mapping["id"] == id {
-			// update mapping
-			mappingFilePath := d.Get("mapping_file_path").(string)
+	return pr, digest, nil
+}
 
-			// read file from mappingFilePath it should be a file path and csv file
-			fInfo, err := os.Stat(mappingFilePath)
-			if err != nil {
-				return diag.Errorf("mapping file not found: %s", mappingFilePath)
-			} else if fInfo.IsDir() {
-				return diag.Errorf("mapping file is a directory: %s", mappingFilePath)
-			}
+// writeMappingBody func writes the mapping body with the rows of the reader appended to it
+func writeMappingBody(w io.Writer, body []byte, reader mappingReader, digest *mappingDigest) error {
+	buf := bufio.NewWriter(w)
+	// open the body object again to append the rows
+	buf.Write(bytes.TrimSuffix(body, []byte("}")))
+	buf.WriteString(`,"rows":[`)
+
+	encoder := json.NewEncoder(buf)
+	for i := 0; ; i++ {
+		row, pos, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read mapping: %s", positionedError(pos, err))
+		}
 
-			file, err := os.OpenFile(mappingFilePath, os.O_RDONLY, 0644)
-			if err != nil {
-				return diag.Errorf("cannot open file: %s", mappingFilePath)
-			}
-			defer file.Close()
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("cannot marshal mapping row: %s", err)
+		}
+		digest.add(row)
+	}
 
-			reader := csv.NewReader(file)
-			records, err := reader.ReadAll()
-			if err != nil {
-				return diag.Errorf("Error reading CSV file: %s", err)
-			}
+	buf.WriteString("]}")
+	return buf.Flush()
+}
 
-			headers := records[0]
-			records = records[1:]
+// streamedDigest is the digest of the rows streamed in a request body
+type streamedDigest struct {
+	*mappingDigest
+	// done is closed once every row is read, it is nil when there are no rows
+	done chan struct{}
+}
 
-			rows := make([]map[string]string, len(records))
-			for i, record := range records {
-				row := make(map[string]string)
-				for j, cell := range record {
-					row[headers[j]] = cell
-				}
-				rows[i] = row
-			}
+// wait func waits for the rows to be streamed and returns their digest
+func (g *streamedDigest) wait() *mappingDigest {
+	if g.done != nil {
+		<-g.done
+	}
+	return g.mappingDigest
+}
 
-			matchers := d.Get("matchers").(*schema.Set).List()
-			//convert matchers to string array
-			matchersStr := make([]string, len(matchers))
-			for i, matcher := range matchers {
-				matchersStr[i] = matcher.(string)
-			}
+// mappingBody func prepares the body of the mapping create and update requests
+func mappingBody(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        d.Get("name").(string),
+		"description": d.Get("description").(string),
+		"matchers":    mappingMatchers(d),
+		"priority":    d.Get("priority").(int),
+		"type":        d.Get("type").(string),
+	}
+}
 
-			reqBody := map[string]interface{}{
-				"name":        d.Get("name").(string),
-				"description": d.Get("description").(string),
-				"matchers":    matchersStr,
-				"priority":    d.Get("priority").(int),
-				"rows":        rows,
-				"file_name":   fInfo.Name(),
-			}
+// mappingMatchers func returns the sorted matchers of the mapping
+func mappingMatchers(d resourceGetter) []string {
+	matchers := d.Get("matchers").(*schema.Set).List()
+	matchersStr := make([]string, len(matchers))
+	for i, matcher := range matchers {
+		matchersStr[i] = matcher.(string)
+	}
+	sort.Strings(matchersStr)
+	return matchersStr
+}
 
-			mappingRule := map[string]interface{}{
-				"name":        mapping["name"],
-				"description": mapping["description"],
-				"matchers":    mapping["matchers"],
-				"priority":    mapping["priority"],
-				"rows":        mapping["rows"],
-				"file_name":   mapping["file_name"],
-			}
+// resourceMappingCustomizeDiff func validates the rows of the mapping at plan time
+func resourceMappingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := customizeMappingRows(d); err != nil {
+		return err
+	}
 
-			// compare request body with existing mapping if one or more fields are different, update mapping
-			if !reflect.DeepEqual(reqBody, mappingRule) {
-				// no changes
-				return nil
-			}
+	if d.Id() != "" && d.HasChanges("name", "description", "matchers", "priority", "type", "mapping_file_path", "format", "delimiter", "rows", "rows_hash") {
+		// keep sets the update time of the mapping
+		d.SetNewComputed("updated_at")
+	}
 
-			bodyBytes, err := json.Marshal(reqBody)
-			if err != nil {
-				return diag.Errorf("cannot request marshal body: %s", err)
-			}
+	return nil
+}
 
-			updateReq, err := http.NewRequest("PUT", client.HostURL+"/mapping/", strings.NewReader(string(bodyBytes)))
-			if err != nil {
-				return diag.Errorf("cannot create request: %s", err)
-			}
+// customizeMappingRows func validates the rows of the mapping and plans the values derived from them
+func customizeMappingRows(d *schema.ResourceDiff) error {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		// there is no configuration to validate when the mapping is destroyed
+		return nil
+	}
 
-			// send request
-			respBody, err := client.doReq(updateReq)
-			if err != nil {
-				return diag.Errorf("cannot send request: %s", err)
+	if d.Get("type").(string) == "topology" {
+		// topology mappings enrich alerts from the service topology, they have no rows
+		for _, key := range []string{"mapping_file_path", "rows", "format", "delimiter"} {
+			if !config.GetAttr(key).IsNull() {
+				return fmt.Errorf("%s is not allowed for topology mappings", key)
 			}
-
-			// unmarshal response
-			var response map[string]interface{}
-			err = json.Unmarshal(respBody, &response)
-			if err != nil {
-				return diag.Errorf("cannot unmarshal response: %s", err)
+		}
+		if d.HasChange("type") {
+			// the rows of a csv mapping are dropped
+			for key, value := range map[string]interface{}{"rows_hash": "", "row_fingerprints": map[string]string{}, "file_name": ""} {
+				if err := d.SetNew(key, value); err != nil {
+					return err
+				}
 			}
-
-			d.SetId(cast.ToString(cast.ToInt(response["id"])))
-			break
+			d.SetNewComputed("attributes")
+			d.SetNewComputed("row_count")
 		}
+		return nil
 	}
 
-	return nil
-}
-
-func resourceDeleteMapping(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+	if config.GetAttr("mapping_file_path").IsNull() && config.GetAttr("rows").IsNull() {
+		return fmt.Errorf("one of mapping_file_path or rows must be specified for csv mappings")
+	}
 
-	id := d.Id()
+	for _, key := range []string{"mapping_file_path", "rows", "matchers", "format", "delimiter"} {
+		if !config.GetAttr(key).IsWhollyKnown() {
+			// unknown values are validated when they are known, rows can't be compared until then
+			if d.Id() != "" {
+				for _, key := range []string{"rows_hash", "attributes", "row_count", "file_name"} {
+					d.SetNewComputed(key)
+				}
+				if d.Get("track_row_changes").(bool) {
+					d.SetNewComputed("row_fingerprints")
+				}
+			}
+			return nil
+		}
+	}
 
-	req, err := http.NewRequest("DELETE", client.HostURL+"/mapping/"+id, nil)
+	reader, fileName, err := openMapping(d)
 	if err != nil {
-		return diag.Errorf("cannot create request: %s", err)
+		return err
 	}
-
-	_, err = client.doReq(req)
+	defer reader.Close()
+
+	matchers := mappingMatchers(d)
+	digest := newMappingDigest(matchers, d.Get("track_row_changes").(bool))
+	rowCount := 0
+	err = validateMapping(reader, matchers, func(row map[string]string) {
+		digest.add(row)
+		rowCount++
+	})
 	if err != nil {
-		return diag.Errorf("cannot send request: %s", err)
+		return fmt.Errorf("invalid mapping:\n%s", err)
+	}
+
+	// the columns that are not matchers are the attributes keep enriches alerts with
+	var attributes []interface{}
+	for _, column := range reader.Headers() {
+		if !slices.Contains(matchers, column) {
+			attributes = append(attributes, column)
+		}
+	}
+	if !d.Get("attributes").(*schema.Set).Equal(schema.NewSet(schema.HashString, attributes)) {
+		if err := d.SetNew("attributes", attributes); err != nil {
+			return err
+		}
+	}
+	if rowCount != d.Get("row_count").(int) {
+		if err := d.SetNew("row_count", rowCount); err != nil {
+			return err
+		}
+	}
+	if fileName != d.Get("file_name").(string) {
+		if err := d.SetNew("file_name", fileName); err != nil {
+			return err
+		}
+	}
+
+	// compare the local rows with the rows read from keep
+	if hash := digest.hash(); hash != d.Get("rows_hash").(string) {
+		if err := d.SetNew("rows_hash", hash); err != nil {
+			return err
+		}
+	}
+	// the fingerprints are empty unless rows are tracked
+	if !reflect.DeepEqual(fingerprintsToInterface(digest.fingerprints), d.Get("row_fingerprints")) {
+		if err := d.SetNew("row_fingerprints", digest.fingerprints); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+// fingerprintsToInterface func converts the fingerprints to the type they are read from the resource data as
+func fingerprintsToInterface(fingerprints map[string]string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(fingerprints))
+	for key, fingerprint := range fingerprints {
+		converted[key] = fingerprint
+	}
+	return converted
+}
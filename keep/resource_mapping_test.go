@@ -0,0 +1,267 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// mockRequest is a request received by the mock keep server
+type mockRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+// mockKeep is a keep server answering every request with the response of its method and path
+type mockKeep struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string]interface{}
+	requests  []mockRequest
+}
+
+// newMockKeep func starts a mock keep server, responses are keyed by the method and the path like "GET /mapping/"
+func newMockKeep(t *testing.T, responses map[string]interface{}) *mockKeep {
+	t.Helper()
+
+	mock := &mockKeep{responses: responses}
+	mock.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := mockRequest{Method: r.Method, Path: r.URL.Path}
+		if content, _ := io.ReadAll(r.Body); len(content) > 0 {
+			if err := json.Unmarshal(content, &request.Body); err != nil {
+				t.Errorf("cannot unmarshal body of %s %s: %s", r.Method, r.URL.Path, err)
+			}
+		}
+
+		mock.mu.Lock()
+		mock.requests = append(mock.requests, request)
+		response, ok := mock.responses[r.Method+" "+r.URL.Path]
+		mock.mu.Unlock()
+
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(mock.Close)
+
+	return mock
+}
+
+// client func returns a client of the mock keep server
+func (m *mockKeep) client() *Client {
+	return &Client{HostURL: m.URL, HTTPClient: m.Server.Client()}
+}
+
+// received func returns the requests received with the method, in the order they were received
+func (m *mockKeep) received(method string) []mockRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var requests []mockRequest
+	for _, request := range m.requests {
+		if request.Method == method {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// reset func forgets the received requests
+func (m *mockKeep) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = nil
+}
+
+// testResourceConfig func returns the configuration of the resource as terraform sends it when planning, along with
+// its raw value the customize diff reads from the prior state
+func testResourceConfig(t *testing.T, r *schema.Resource, raw map[string]interface{}) (*terraform.ResourceConfig, cty.Value) {
+	t.Helper()
+
+	content, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("cannot marshal config: %s", err)
+	}
+	value, err := ctyjson.Unmarshal(content, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("cannot unmarshal config: %s", err)
+	}
+
+	return terraform.NewResourceConfigShimmed(value, r.CoreConfigSchema()), value
+}
+
+var testMapping = map[string]interface{}{
+	"id":          7,
+	"name":        "service_owners",
+	"description": "owners of the services",
+	"priority":    0,
+	"type":        "csv",
+	"matchers":    []string{"service"},
+	"attributes":  []string{"owner"},
+	"rows": []map[string]interface{}{
+		{"service": "checkout", "owner": "payments"},
+	},
+}
+
+var testMappingConfig = map[string]interface{}{
+	"name":        "service_owners",
+	"description": "owners of the services",
+	"matchers":    []interface{}{"service"},
+	"rows": []interface{}{
+		map[string]interface{}{"service": "checkout", "owner": "payments"},
+	},
+}
+
+// readTestMapping func returns the state of the mapping as read from the mock keep server
+func readTestMapping(t *testing.T, mock *mockKeep) *terraform.InstanceState {
+	t.Helper()
+
+	r := resourceMapping()
+	d := r.Data(&terraform.InstanceState{ID: "7"})
+	if diags := resourceReadMapping(context.Background(), d, mock.client()); diags.HasError() {
+		t.Fatalf("cannot read mapping: %v", diags)
+	}
+	mock.reset()
+
+	return d.State()
+}
+
+func TestResourceUpdateMapping(t *testing.T) {
+	mock := newMockKeep(t, map[string]interface{}{
+		"GET /mapping/":  []interface{}{testMapping},
+		"PUT /mapping/7": testMapping,
+	})
+	state := readTestMapping(t, mock)
+
+	config := make(map[string]interface{})
+	for key, value := range testMappingConfig {
+		config[key] = value
+	}
+	config["description"] = "teams owning the services"
+
+	r := resourceMapping()
+	resourceConfig, rawConfig := testResourceConfig(t, r, config)
+	state.RawConfig = rawConfig
+	diff, err := r.Diff(context.Background(), state, resourceConfig, mock.client())
+	if err != nil {
+		t.Fatalf("cannot diff mapping: %s", err)
+	}
+
+	newState, diags := r.Apply(context.Background(), state, diff, mock.client())
+	if diags.HasError() {
+		t.Fatalf("cannot update mapping: %v", diags)
+	}
+
+	puts := mock.received("PUT")
+	if len(puts) != 1 {
+		t.Fatalf("expected 1 PUT request, got %d", len(puts))
+	}
+	if puts[0].Path != "/mapping/7" {
+		t.Errorf("expected PUT /mapping/7, got PUT %s", puts[0].Path)
+	}
+	if id := puts[0].Body["id"]; id != float64(7) {
+		t.Errorf("expected id 7 in the body, got %v", id)
+	}
+	if description := puts[0].Body["description"]; description != "teams owning the services" {
+		t.Errorf("expected the new description in the body, got %v", description)
+	}
+	if posts := mock.received("POST"); len(posts) != 0 {
+		t.Errorf("expected no POST request, got %d", len(posts))
+	}
+	if newState.ID != "7" {
+		t.Errorf("expected the id to stay 7, got %s", newState.ID)
+	}
+}
+
+func TestResourceUpdateMappingWithoutChanges(t *testing.T) {
+	mock := newMockKeep(t, map[string]interface{}{
+		"GET /mapping/": []interface{}{testMapping},
+	})
+	state := readTestMapping(t, mock)
+
+	// the rows are compared by their hash planned from the configuration
+	r := resourceMapping()
+	resourceConfig, rawConfig := testResourceConfig(t, r, testMappingConfig)
+	state.RawConfig = rawConfig
+	diff, err := r.Diff(context.Background(), state, resourceConfig, mock.client())
+	if err != nil {
+		t.Fatalf("cannot diff mapping: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no changes, got %v", diff.Attributes)
+	}
+
+	// the update sends nothing when called without changes
+	d := r.Data(state)
+	if diags := resourceUpdateMapping(context.Background(), d, mock.client()); diags.HasError() {
+		t.Fatalf("cannot update mapping: %v", diags)
+	}
+	if len(mock.requests) != 0 {
+		t.Errorf("expected no request, got %v", mock.requests)
+	}
+	if d.Id() != "7" {
+		t.Errorf("expected the id to stay 7, got %s", d.Id())
+	}
+}
+
+func TestResourceDiffMappingRows(t *testing.T) {
+	mock := newMockKeep(t, map[string]interface{}{
+		"GET /mapping/": []interface{}{testMapping},
+	})
+	state := readTestMapping(t, mock)
+
+	config := make(map[string]interface{})
+	for key, value := range testMappingConfig {
+		config[key] = value
+	}
+	config["rows"] = []interface{}{
+		map[string]interface{}{"service": "checkout", "owner": "payments"},
+		map[string]interface{}{"service": "search", "owner": "discovery"},
+	}
+
+	r := resourceMapping()
+	resourceConfig, rawConfig := testResourceConfig(t, r, config)
+	state.RawConfig = rawConfig
+	diff, err := r.Diff(context.Background(), state, resourceConfig, mock.client())
+	if err != nil {
+		t.Fatalf("cannot diff mapping: %s", err)
+	}
+
+	for _, key := range []string{"rows_hash", "row_count"} {
+		if attribute, ok := diff.Attributes[key]; !ok || attribute.NewComputed || attribute.New == attribute.Old {
+			t.Errorf("expected %s to be planned from the new rows, got %v", key, attribute)
+		}
+	}
+	if attribute := diff.Attributes["row_count"]; attribute != nil && attribute.New != "2" {
+		t.Errorf("expected row_count to be planned as 2, got %s", attribute.New)
+	}
+	if attribute, ok := diff.Attributes["updated_at"]; !ok || !attribute.NewComputed {
+		t.Errorf("expected updated_at to be unknown until keep updates the mapping, got %v", attribute)
+	}
+}
+
+func TestResourceDiffMappingWithoutRawConfig(t *testing.T) {
+	mock := newMockKeep(t, map[string]interface{}{
+		"GET /mapping/": []interface{}{testMapping},
+	})
+	state := readTestMapping(t, mock)
+
+	// terraform sends no configuration when the mapping is destroyed
+	r := resourceMapping()
+	if _, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(nil), mock.client()); err != nil {
+		t.Fatalf("cannot diff mapping: %s", err)
+	}
+}
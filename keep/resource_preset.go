@@ -0,0 +1,252 @@
+package keep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourcePreset() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCreatePreset,
+		ReadContext:   resourceReadPreset,
+		UpdateContext: resourceUpdatePreset,
+		DeleteContext: resourceDeletePreset,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportPreset,
+		},
+		Description: "Manages a preset, a saved view of the alert feed filtered by a CEL query.",
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the preset",
+			},
+			"cel_query": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "CEL query alerts of the preset match, e.g. `severity == \"critical\" && source.contains(\"prometheus\")`. Keep's alert feed needs the query as SQL too, so only the queries the feed can build are supported: comparisons of alert fields with literal values using `==`, `!=`, `<`, `<=`, `>`, `>=`, `in`, `contains`, `startsWith` and `endsWith`, combined with `&&`, `||` and `!`",
+				ValidateDiagFunc: validateCELSQL,
+			},
+			"is_private": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the preset is only visible to its creator (default: false)",
+			},
+			"is_noisy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the preset makes noise when it has firing alerts (default: false)",
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Tags of the preset",
+			},
+			"should_do_noise_now": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the preset currently makes noise",
+			},
+			"created_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creator of the preset",
+			},
+		},
+	}
+}
+
+func resourceCreatePreset(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	body, err := presetBody(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// marshal body
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return diag.Errorf("cannot marshal preset body: %s", err)
+	}
+
+	// create preset
+	req, err := http.NewRequest("POST", client.HostURL+"/preset", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response Preset
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	d.SetId(response.ID)
+	setPreset(d, response)
+
+	return nil
+}
+
+func resourceReadPreset(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	presets, err := getPresets(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, preset := range presets {
+		if preset.ID == d.Id() {
+			setPreset(d, preset)
+			return nil
+		}
+	}
+
+	// preset is deleted
+	d.SetId("")
+
+	return nil
+}
+
+func resourceUpdatePreset(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	if !d.HasChanges("name", "cel_query", "is_private", "is_noisy", "tags") {
+		return nil
+	}
+
+	body, err := presetBody(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// marshal body
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return diag.Errorf("cannot marshal preset body: %s", err)
+	}
+
+	// update preset
+	req, err := http.NewRequest("PUT", client.HostURL+"/preset/"+d.Id(), strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	// send request
+	respBody, err := client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	// unmarshal response
+	var response Preset
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return diag.Errorf("cannot unmarshal response: %s", err)
+	}
+
+	setPreset(d, response)
+
+	return nil
+}
+
+func resourceDeletePreset(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	req, err := http.NewRequest("DELETE", client.HostURL+"/preset/"+d.Id(), nil)
+	if err != nil {
+		return diag.Errorf("cannot create request: %s", err)
+	}
+
+	_, err = client.doReq(req)
+	if err != nil {
+		return diag.Errorf("cannot send request: %s", err)
+	}
+
+	return nil
+}
+
+// resourceImportPreset func imports presets by id or by name
+func resourceImportPreset(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	id := d.Id()
+
+	presets, err := getPresets(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, preset := range presets {
+		if preset.ID == id || preset.Name == id {
+			if preset.Static {
+				// the feed and the other presets keep comes with can't be changed
+				return nil, fmt.Errorf("preset %s is a static preset of keep and can't be managed", id)
+			}
+
+			d.SetId(preset.ID)
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("preset not found: %s", id)
+}
+
+// presetBody func prepares the body of the preset create and update requests,
+// keep filters the alerts with the CEL query and its alert feed shows the SQL one
+func presetBody(d *schema.ResourceData) (map[string]interface{}, error) {
+	celQuery := d.Get("cel_query").(string)
+
+	sql, params, err := celToSQL(celQuery)
+	if err != nil {
+		return nil, fmt.Errorf("cannot translate cel_query to SQL: %s", err)
+	}
+
+	tags := make([]PresetTag, 0)
+	for _, tag := range d.Get("tags").(*schema.Set).List() {
+		// keep creates the tags that don't exist yet
+		tags = append(tags, PresetTag{Name: tag.(string)})
+	}
+
+	return map[string]interface{}{
+		"name": d.Get("name").(string),
+		"options": []PresetOption{
+			{Label: "CEL", Value: celQuery},
+			{Label: "SQL", Value: map[string]interface{}{
+				"sql":    sql,
+				"params": params,
+			}},
+		},
+		"is_private": d.Get("is_private").(bool),
+		"is_noisy":   d.Get("is_noisy").(bool),
+		"tags":       tags,
+	}, nil
+}
+
+// setPreset func sets the attributes of the preset as keep returns it
+func setPreset(d *schema.ResourceData, preset Preset) {
+	d.Set("name", preset.Name)
+	d.Set("cel_query", preset.CELQuery())
+	d.Set("is_private", preset.IsPrivate)
+	d.Set("is_noisy", preset.IsNoisy)
+	d.Set("tags", preset.TagNames())
+	d.Set("should_do_noise_now", preset.ShouldDoNoiseNow)
+	d.Set("created_by", preset.CreatedBy)
+}
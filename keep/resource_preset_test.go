@@ -0,0 +1,74 @@
+package keep
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceCreatePresetSendsSQL(t *testing.T) {
+	mock := newMockKeep(t, map[string]interface{}{
+		"POST /preset": map[string]interface{}{
+			"id":   "5f1c",
+			"name": "critical",
+			"options": []interface{}{
+				map[string]interface{}{"label": "CEL", "value": `severity == "critical" && source.contains("prometheus")`},
+			},
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, resourcePreset().Schema, map[string]interface{}{
+		"name":      "critical",
+		"cel_query": `severity == "critical" && source.contains("prometheus")`,
+	})
+	if diags := resourceCreatePreset(context.Background(), d, mock.client()); diags.HasError() {
+		t.Fatalf("cannot create preset: %v", diags)
+	}
+
+	posts := mock.received("POST")
+	if len(posts) != 1 {
+		t.Fatalf("expected 1 POST request, got %d", len(posts))
+	}
+	expected := []interface{}{
+		map[string]interface{}{"label": "CEL", "value": `severity == "critical" && source.contains("prometheus")`},
+		map[string]interface{}{"label": "SQL", "value": map[string]interface{}{
+			"sql": `(severity = :severity_1 and source like :source_1 escape '\')`,
+			"params": map[string]interface{}{
+				"severity_1": "critical",
+				"source_1":   "%prometheus%",
+			},
+		}},
+	}
+	if options := posts[0].Body["options"]; !reflect.DeepEqual(options, expected) {
+		t.Errorf("expected options %v, got %v", expected, options)
+	}
+	if d.Id() != "5f1c" {
+		t.Errorf("expected id 5f1c, got %s", d.Id())
+	}
+}
+
+func TestDataSourceReadPresetDuplicatedName(t *testing.T) {
+	mock := newMockKeep(t, map[string]interface{}{
+		"GET /preset": []interface{}{
+			map[string]interface{}{"id": "5f1c", "name": "critical"},
+			map[string]interface{}{"id": "9a2e", "name": "critical"},
+			map[string]interface{}{"id": "7b3d", "name": "noisy"},
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourcePreset().Schema, map[string]interface{}{"name": "critical"})
+	if diags := dataSourceReadPreset(context.Background(), d, mock.client()); !diags.HasError() {
+		t.Errorf("expected an error for a name shared by several presets, got preset %s", d.Id())
+	}
+
+	// the id selects one of them
+	d = schema.TestResourceDataRaw(t, dataSourcePreset().Schema, map[string]interface{}{"id": "9a2e"})
+	if diags := dataSourceReadPreset(context.Background(), d, mock.client()); diags.HasError() {
+		t.Fatalf("cannot read preset: %v", diags)
+	}
+	if d.Id() != "9a2e" || d.Get("name") != "critical" {
+		t.Errorf("expected preset 9a2e, got %s named %v", d.Id(), d.Get("name"))
+	}
+}
@@ -0,0 +1,109 @@
+package keep
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cast"
+)
+
+// typedProvider describes a provider type of keep's provider catalog, the typed provider resources are generated from it
+type typedProvider struct {
+	// ResourceName is the provider type made a valid terraform name, the resource is named keep_provider_<ResourceName>
+	ResourceName string
+	DisplayName  string
+	Fields       map[string]*schema.Schema
+}
+
+// resourceTypedProvider func returns the keep_provider_<type> resource of the provider type
+func resourceTypedProvider(providerType string, provider typedProvider) *schema.Resource {
+	h := typedAuthConfig{
+		fixedType: providerType,
+		fields:    provider.Fields,
+	}
+
+	required := false
+	for _, field := range provider.Fields {
+		required = required || field.Required
+	}
+
+	providerSchema := providerCommonSchema()
+	providerSchema["auth_config"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Required:    required,
+		Optional:    !required,
+		MaxItems:    1,
+		Description: fmt.Sprintf("Configuration of the %s provider authentication. Secret values can't be read back from keep.", provider.DisplayName),
+		Elem: &schema.Resource{
+			Schema: provider.Fields,
+		},
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceCreateProvider(h),
+		ReadContext:   resourceReadProvider(h),
+		UpdateContext: resourceUpdateProvider(h),
+		DeleteContext: resourceDeleteProvider(h),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportProvider(h),
+		},
+		Description: fmt.Sprintf("Manages a %s provider installed in keep.\n\n", provider.DisplayName) + providerSecretsNote,
+		Schema:      providerSchema,
+	}
+}
+
+// typedAuthConfig keeps the authentication config of a typed provider resource in a single auth_config block
+type typedAuthConfig struct {
+	fixedType string
+	fields    map[string]*schema.Schema
+}
+
+func (c typedAuthConfig) providerType(d *schema.ResourceData) string {
+	return c.fixedType
+}
+
+func (c typedAuthConfig) authConfig(d *schema.ResourceData) map[string]interface{} {
+	authConfig := make(map[string]interface{})
+	for key, value := range c.current(d) {
+		// unset optional fields are not sent, so keep applies its defaults
+		if value == "" {
+			continue
+		}
+		authConfig[key] = value
+	}
+	return authConfig
+}
+
+func (c typedAuthConfig) read(d *schema.ResourceData, provider InstalledProvider) error {
+	current := c.current(d)
+	authentication, _ := provider.Details["authentication"].(map[string]interface{})
+
+	authConfig := make(map[string]interface{}, len(c.fields))
+	for key, field := range c.fields {
+		value, ok := authentication[key]
+		if field.Sensitive || !ok || value == nil {
+			authConfig[key] = current[key]
+			continue
+		}
+
+		switch field.Type {
+		case schema.TypeBool:
+			authConfig[key] = cast.ToBool(value)
+		case schema.TypeInt:
+			authConfig[key] = cast.ToInt(value)
+		default:
+			authConfig[key] = cast.ToString(value)
+		}
+	}
+
+	return d.Set("auth_config", []interface{}{authConfig})
+}
+
+// current func returns the authentication config block as it is in the resource data
+func (c typedAuthConfig) current(d *schema.ResourceData) map[string]interface{} {
+	blocks := d.Get("auth_config").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return map[string]interface{}{}
+	}
+	return blocks[0].(map[string]interface{})
+}